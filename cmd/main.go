@@ -8,10 +8,13 @@ import (
 	"syscall"
 
 	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/auth"
 	"github.com/khdiyz/media-service/internal/config"
 	"github.com/khdiyz/media-service/internal/handler"
+	"github.com/khdiyz/media-service/internal/metadata"
 	"github.com/khdiyz/media-service/internal/service"
 	"github.com/khdiyz/media-service/internal/storage"
+	"github.com/khdiyz/media-service/internal/transform"
 	mediav1 "github.com/khdiyz/media-service/proto/media/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -27,14 +30,31 @@ func main() {
 	// Load configuration
 	cfg := config.GetConfig(log)
 
-	// Initialize Storage (MinIO)
-	minioStorage, err := storage.NewMinioStorage(cfg, log)
+	// Initialize Storage
+	fileStorage, err := storage.New(cfg, log)
 	if err != nil {
-		log.Fatalw("Failed to initialize MinIO storage", "error", err)
+		log.Fatalw("Failed to initialize storage", "backend", cfg.StorageBackend, "error", err)
 	}
 
+	// Initialize metadata store
+	metadataStore, err := metadata.New(cfg)
+	if err != nil {
+		log.Fatalw("Failed to initialize metadata store", "backend", cfg.MetadataBackend, "error", err)
+	}
+
+	// Initialize per-object authorization (no-op until an auth chain is
+	// configured below)
+	var authorizer *service.Authorizer
+	if cfg.AuthEnabled {
+		authorizer = service.NewAuthorizer(metadataStore)
+	}
+
+	// Initialize image transform pipeline (thumbnails/previews generated on
+	// upload, arbitrary resizes cached on first request)
+	transformService := transform.New(fileStorage, log)
+
 	// Initialize Service
-	mediaService := service.NewMediaService(minioStorage, log)
+	mediaService := service.NewMediaService(fileStorage, metadataStore, authorizer, transformService, log)
 
 	// Initialize Handler
 	mediaHandler := handler.NewMediaHandler(mediaService, log)
@@ -45,7 +65,17 @@ func main() {
 		log.Fatalw("Failed to listen", "error", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	var serverOpts []grpc.ServerOption
+	if cfg.AuthEnabled {
+		chain := newAuthChain(cfg)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(chain)),
+			grpc.ChainStreamInterceptor(auth.StreamServerInterceptor(chain)),
+		)
+		log.Infow("Auth interceptor chain enabled", "backend", cfg.AuthBackend)
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	mediav1.RegisterMediaServiceServer(grpcServer, mediaHandler)
 
 	// Enable reflection for debugging (e.g. using grpcurl)
@@ -65,3 +95,23 @@ func main() {
 		log.Fatalw("Failed to serve gRPC", "error", err)
 	}
 }
+
+// newAuthChain builds the auth.Chain for the configured backend: Basic-auth
+// credentials are validated against static users or LDAP (per
+// cfg.AuthBackend), and bearer tokens are always validated as JWTs against
+// cfg.JWTSecret.
+func newAuthChain(cfg *config.Config) *auth.Chain {
+	chain := auth.NewChain()
+
+	var basicHandler auth.CredsHandler
+	switch cfg.AuthBackend {
+	case "ldap":
+		basicHandler = auth.NewLDAPCredsHandler(cfg.LDAPAddr, cfg.LDAPBindDNTmpl)
+	default:
+		basicHandler = auth.NewStaticUserCredsHandler(auth.ParseStaticUsers(cfg.StaticUsers))
+	}
+	chain.Add(auth.BasicAuthExtract{}, basicHandler)
+	chain.Add(auth.BearerAuthExtract{}, auth.NewJWTCredsHandler([]byte(cfg.JWTSecret)))
+
+	return chain
+}
@@ -0,0 +1,30 @@
+// Package auth provides gRPC authentication for MediaService: extracting
+// credentials from the incoming request (Basic auth, JWT bearer tokens),
+// validating them against a pluggable backend (static users, LDAP), and
+// attaching the resulting Identity to the request context for the service
+// layer's Authorizer to consult.
+package auth
+
+import "context"
+
+// Identity is the authenticated caller, attached to the context by the
+// auth interceptor and consulted by service.Authorizer for per-object ACL
+// checks.
+type Identity struct {
+	Subject string
+	Groups  []string
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying identity.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the Identity attached by the auth
+// interceptor, if any.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
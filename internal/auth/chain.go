@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// pair couples an Extractor with the CredsHandler that validates whatever it extracts.
+type pair struct {
+	extractor Extractor
+	handler   CredsHandler
+}
+
+// Chain authenticates a request by trying each registered
+// (Extractor, CredsHandler) pair in order, returning the first successful Identity.
+type Chain struct {
+	pairs []pair
+}
+
+// NewChain creates an empty auth Chain. Use Add to register pairs.
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// Add registers an (Extractor, CredsHandler) pair, tried in the order added.
+func (c *Chain) Add(extractor Extractor, handler CredsHandler) *Chain {
+	c.pairs = append(c.pairs, pair{extractor: extractor, handler: handler})
+	return c
+}
+
+// Authenticate returns the first successful Identity from the registered
+// pairs, or ErrInvalidCredentials/ErrNoCredentials if none succeed.
+func (c *Chain) Authenticate(ctx context.Context) (*Identity, error) {
+	sawCredentials := false
+
+	for _, p := range c.pairs {
+		creds, err := p.extractor.Extract(ctx)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		identity, err := p.handler.Handle(ctx, creds)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if errors.Is(err, ErrInvalidCredentials) {
+			sawCredentials = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return identity, nil
+	}
+
+	if sawCredentials {
+		return nil, ErrInvalidCredentials
+	}
+	return nil, ErrNoCredentials
+}
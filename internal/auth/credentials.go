@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrNoCredentials is returned by an Extractor when the incoming request
+// carries no credentials it recognizes, so the auth chain can fall through
+// to the next (extractor, handler) pair.
+var ErrNoCredentials = errors.New("auth: no credentials in request")
+
+// Credentials is the raw, unvalidated material an Extractor pulls out of a
+// request. Exactly one of (Username/Password) or Token is populated,
+// depending on Scheme.
+type Credentials struct {
+	Scheme   string // "basic" or "bearer"
+	Username string
+	Password string
+	Token    string
+}
+
+// Extractor pulls Credentials out of an incoming gRPC request, without
+// validating them. This is the "extraction" step of the
+// extraction -> validation -> authorization pipeline.
+type Extractor interface {
+	Extract(ctx context.Context) (*Credentials, error)
+}
+
+func authorizationHeader(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ErrNoCredentials
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", ErrNoCredentials
+	}
+	return values[0], nil
+}
+
+// BasicAuthExtract extracts HTTP Basic-auth style credentials from the
+// "authorization: Basic <base64(user:pass)>" metadata header.
+type BasicAuthExtract struct{}
+
+// Extract implements Extractor.
+func (BasicAuthExtract) Extract(ctx context.Context) (*Credentials, error) {
+	header, err := authorizationHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const prefix = "Basic "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, ErrNoCredentials
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, prefix))
+	if err != nil {
+		return nil, errors.New("auth: malformed basic auth header")
+	}
+
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, errors.New("auth: malformed basic auth header")
+	}
+
+	return &Credentials{Scheme: "basic", Username: username, Password: password}, nil
+}
+
+// BearerAuthExtract extracts a JWT from the
+// "authorization: Bearer <token>" metadata header.
+type BearerAuthExtract struct{}
+
+// Extract implements Extractor.
+func (BearerAuthExtract) Extract(ctx context.Context) (*Credentials, error) {
+	header, err := authorizationHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, ErrNoCredentials
+	}
+
+	return &Credentials{Scheme: "bearer", Token: strings.TrimPrefix(header, prefix)}, nil
+}
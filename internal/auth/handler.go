@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParseStaticUsers parses a "user:pass,user2:pass2" table into the map
+// NewStaticUserCredsHandler expects. Malformed entries are skipped.
+func ParseStaticUsers(s string) map[string]string {
+	users := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		username, password, ok := strings.Cut(entry, ":")
+		if !ok || username == "" {
+			continue
+		}
+		users[username] = password
+	}
+	return users
+}
+
+// ErrInvalidCredentials is returned by a CredsHandler when the credentials
+// it was asked to validate are well-formed but incorrect.
+var ErrInvalidCredentials = errors.New("auth: invalid credentials")
+
+// CredsHandler validates Credentials produced by an Extractor and returns
+// the resulting Identity. This is the "validation" step of the
+// extraction -> validation -> authorization pipeline.
+type CredsHandler interface {
+	Handle(ctx context.Context, creds *Credentials) (*Identity, error)
+}
+
+// StaticUserCredsHandler validates Basic-auth credentials against a fixed,
+// in-memory user table. Intended for small deployments and tests; LDAP or
+// an external IdP should back anything larger.
+type StaticUserCredsHandler struct {
+	// users maps username -> password. Passwords are compared in constant
+	// time but are not hashed, matching the static/dev-only nature of this
+	// backend.
+	users map[string]string
+}
+
+// NewStaticUserCredsHandler creates a StaticUserCredsHandler from a
+// username -> password table.
+func NewStaticUserCredsHandler(users map[string]string) *StaticUserCredsHandler {
+	return &StaticUserCredsHandler{users: users}
+}
+
+// Handle implements CredsHandler.
+func (h *StaticUserCredsHandler) Handle(ctx context.Context, creds *Credentials) (*Identity, error) {
+	if creds.Scheme != "basic" {
+		return nil, ErrNoCredentials
+	}
+
+	password, ok := h.users[creds.Username]
+	if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(creds.Password)) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Identity{Subject: creds.Username}, nil
+}
+
+// LDAPCredsHandler validates Basic-auth credentials by binding to an LDAP
+// directory as the presented user.
+type LDAPCredsHandler struct {
+	Addr       string // "ldap://host:389"
+	BindDNTmpl string // e.g. "uid=%s,ou=people,dc=example,dc=com"
+}
+
+// NewLDAPCredsHandler creates an LDAPCredsHandler.
+func NewLDAPCredsHandler(addr, bindDNTmpl string) *LDAPCredsHandler {
+	return &LDAPCredsHandler{Addr: addr, BindDNTmpl: bindDNTmpl}
+}
+
+// Handle implements CredsHandler.
+func (h *LDAPCredsHandler) Handle(ctx context.Context, creds *Credentials) (*Identity, error) {
+	if creds.Scheme != "basic" {
+		return nil, ErrNoCredentials
+	}
+
+	conn, err := ldap.DialURL(h.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to connect to ldap: %w", err)
+	}
+	defer conn.Close()
+
+	bindDN := fmt.Sprintf(h.BindDNTmpl, creds.Username)
+	if err := conn.Bind(bindDN, creds.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Identity{Subject: creds.Username}, nil
+}
+
+// JWTCredsHandler validates bearer tokens signed with an HMAC secret and
+// derives an Identity from the "sub" and "groups" claims.
+type JWTCredsHandler struct {
+	secret []byte
+}
+
+// NewJWTCredsHandler creates a JWTCredsHandler.
+func NewJWTCredsHandler(secret []byte) *JWTCredsHandler {
+	return &JWTCredsHandler{secret: secret}
+}
+
+// Handle implements CredsHandler.
+func (h *JWTCredsHandler) Handle(ctx context.Context, creds *Credentials) (*Identity, error) {
+	if creds.Scheme != "bearer" {
+		return nil, ErrNoCredentials
+	}
+
+	token, err := jwt.Parse(creds.Token, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return h.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	var groups []string
+	if raw, ok := claims["groups"].([]any); ok {
+		for _, g := range raw {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+
+	return &Identity{Subject: subject, Groups: groups}, nil
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor authenticates every unary RPC against chain and
+// attaches the resulting Identity to the request context before invoking
+// the handler.
+func UnaryServerInterceptor(chain *Chain) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		identity, err := chain.Authenticate(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+		}
+
+		return handler(WithIdentity(ctx, identity), req)
+	}
+}
+
+// StreamServerInterceptor authenticates every streaming RPC against chain
+// and attaches the resulting Identity to the stream's context before
+// invoking the handler.
+func StreamServerInterceptor(chain *Chain) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		identity, err := chain.Authenticate(ss.Context())
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: WithIdentity(ss.Context(), identity)})
+	}
+}
+
+// authenticatedStream overrides Context() so downstream handlers see the
+// identity attached by StreamServerInterceptor.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
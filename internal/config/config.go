@@ -18,12 +18,49 @@ type Config struct {
 	GrpcHost string
 	GrpcPort int
 
+	// StorageBackend selects which Storage implementation storage.New wires
+	// up: "minio" (default), "azure", "gcs" or "fs".
+	StorageBackend string
+
 	MinioEndpoint   string
 	MinioAccessKey  string
 	MinioSecretKey  string
 	MinioUseSSL     bool
 	MinioBucketName string
 	MinioFileUrl    string
+
+	AzureAccountName   string
+	AzureAccountKey    string
+	AzureContainerName string
+
+	GCSBucketName      string
+	GCSCredentialsFile string
+
+	LocalBasePath string
+	LocalBaseURL  string
+
+	// MetadataBackend selects which metadata.MetadataStore implementation
+	// is wired up: "memory" (default) or "postgres".
+	MetadataBackend string
+	PostgresDSN     string
+
+	// AuthEnabled gates whether cmd/main.go installs the auth interceptor
+	// chain at all; with it disabled the service runs with no
+	// authentication or per-object authorization, as before.
+	AuthEnabled bool
+
+	// AuthBackend selects the CredsHandler used to validate Basic-auth
+	// credentials: "static" (default) or "ldap". JWT bearer tokens are
+	// always validated against JWTSecret when AuthEnabled.
+	AuthBackend string
+	// StaticUsers is a comma-separated "user:pass,user2:pass2" table used
+	// when AuthBackend is "static".
+	StaticUsers string
+
+	LDAPAddr       string
+	LDAPBindDNTmpl string
+
+	JWTSecret string
 }
 
 func GetConfig(log *logger.Logger) *Config {
@@ -36,12 +73,36 @@ func GetConfig(log *logger.Logger) *Config {
 			GrpcHost: cast.ToString(getOrReturnDefault("GRPC_HOST", "localhost")),
 			GrpcPort: cast.ToInt(getOrReturnDefault("GRPC_PORT", 5051)),
 
+			StorageBackend: cast.ToString(getOrReturnDefault("STORAGE_BACKEND", "minio")),
+
 			MinioEndpoint:   cast.ToString(getOrReturnDefault("MINIO_ENDPOINT", "")),
 			MinioAccessKey:  cast.ToString(getOrReturnDefault("MINIO_ACCESS_KEY", "")),
 			MinioSecretKey:  cast.ToString(getOrReturnDefault("MINIO_SECRET_KEY", "")),
 			MinioUseSSL:     cast.ToBool(getOrReturnDefault("MINIO_USE_SSL", true)),
 			MinioBucketName: cast.ToString(getOrReturnDefault("MINIO_BUCKET_NAME", "")),
 			MinioFileUrl:    cast.ToString(getOrReturnDefault("MINIO_FILE_URL", "")),
+
+			AzureAccountName:   cast.ToString(getOrReturnDefault("AZURE_ACCOUNT_NAME", "")),
+			AzureAccountKey:    cast.ToString(getOrReturnDefault("AZURE_ACCOUNT_KEY", "")),
+			AzureContainerName: cast.ToString(getOrReturnDefault("AZURE_CONTAINER_NAME", "")),
+
+			GCSBucketName:      cast.ToString(getOrReturnDefault("GCS_BUCKET_NAME", "")),
+			GCSCredentialsFile: cast.ToString(getOrReturnDefault("GCS_CREDENTIALS_FILE", "")),
+
+			LocalBasePath: cast.ToString(getOrReturnDefault("LOCAL_BASE_PATH", "./data")),
+			LocalBaseURL:  cast.ToString(getOrReturnDefault("LOCAL_BASE_URL", "")),
+
+			MetadataBackend: cast.ToString(getOrReturnDefault("METADATA_BACKEND", "memory")),
+			PostgresDSN:     cast.ToString(getOrReturnDefault("POSTGRES_DSN", "")),
+
+			AuthEnabled: cast.ToBool(getOrReturnDefault("AUTH_ENABLED", false)),
+			AuthBackend: cast.ToString(getOrReturnDefault("AUTH_BACKEND", "static")),
+			StaticUsers: cast.ToString(getOrReturnDefault("STATIC_USERS", "")),
+
+			LDAPAddr:       cast.ToString(getOrReturnDefault("LDAP_ADDR", "")),
+			LDAPBindDNTmpl: cast.ToString(getOrReturnDefault("LDAP_BIND_DN_TEMPLATE", "")),
+
+			JWTSecret: cast.ToString(getOrReturnDefault("JWT_SECRET", "")),
 		}
 	})
 	return instance
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/metadata"
 	"github.com/khdiyz/media-service/internal/service"
+	"github.com/khdiyz/media-service/internal/storage"
+	"github.com/khdiyz/media-service/internal/transform"
 	mediav1 "github.com/khdiyz/media-service/proto/media/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -73,9 +76,13 @@ func (h *MediaHandler) UploadStream(stream mediav1.MediaService_UploadStreamServ
 	}
 	resultChan := make(chan uploadResult, 1)
 
+	// Capture the stream context before the goroutine so the authenticated
+	// identity is preserved for ACL ownership on the uploaded file.
+	ctx := stream.Context()
+
 	// Start upload in a goroutine
 	go func() {
-		filePath, err := h.service.UploadStream(context.Background(), metadata.FileName, metadata.FileSize, reader, metadata.ContentType)
+		filePath, err := h.service.UploadStream(ctx, metadata.FileName, metadata.FileSize, reader, metadata.ContentType)
 		resultChan <- uploadResult{filePath: filePath, err: err}
 	}()
 
@@ -125,6 +132,10 @@ func (h *MediaHandler) UploadStream(stream mediav1.MediaService_UploadStreamServ
 func (h *MediaHandler) Download(ctx context.Context, req *mediav1.DownloadRequest) (*mediav1.DownloadResponse, error) {
 	h.log.Infow("Download request received", "file_path", req.FilePath)
 
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
 	reader, err := h.service.DownloadFile(ctx, req.FilePath)
 	if err != nil {
 		h.log.Errorw("Failed to download file", "file_path", req.FilePath, "error", err)
@@ -137,13 +148,21 @@ func (h *MediaHandler) Download(ctx context.Context, req *mediav1.DownloadReques
 		return nil, status.Errorf(codes.Internal, "failed to read file content: %v", err)
 	}
 
-	// Note: In a real scenario, we might want to store/retrieve content type and original filename
-	// For now, we'll return generic values or what we can derive
+	fileName := req.FilePath
+	contentType := "application/octet-stream"
+	var etag string
+	if info, err := h.service.GetFileInfo(ctx, req.FilePath); err == nil {
+		fileName = info.FileName
+		contentType = info.ContentType
+		etag = info.Checksum
+	}
+
 	return &mediav1.DownloadResponse{
-		FileName:    req.FilePath, // We don't store original name separately in this simple impl
+		FileName:    fileName,
 		Content:     content,
-		ContentType: "application/octet-stream", // Default
+		ContentType: contentType,
 		FileSize:    int64(len(content)),
+		Etag:        etag,
 	}, nil
 }
 
@@ -151,17 +170,29 @@ func (h *MediaHandler) Download(ctx context.Context, req *mediav1.DownloadReques
 func (h *MediaHandler) DownloadStream(req *mediav1.DownloadRequest, stream mediav1.MediaService_DownloadStreamServer) error {
 	h.log.Infow("DownloadStream request received", "file_path", req.FilePath)
 
+	if err := h.service.Authorize(stream.Context(), req.FilePath); err != nil {
+		return status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
 	reader, err := h.service.DownloadFile(stream.Context(), req.FilePath)
 	if err != nil {
 		return status.Errorf(codes.NotFound, "file not found: %v", err)
 	}
 	defer reader.Close()
 
+	fileName := req.FilePath
+	var etag string
+	if info, err := h.service.GetFileInfo(stream.Context(), req.FilePath); err == nil {
+		fileName = info.FileName
+		etag = info.Checksum
+	}
+
 	// Send metadata first
 	err = stream.Send(&mediav1.DownloadStreamResponse{
 		Data: &mediav1.DownloadStreamResponse_Metadata{
 			Metadata: &mediav1.FileMetadata{
-				FileName: req.FilePath,
+				FileName: fileName,
+				Etag:     etag,
 			},
 		},
 	})
@@ -198,6 +229,10 @@ func (h *MediaHandler) DownloadStream(req *mediav1.DownloadRequest, stream media
 func (h *MediaHandler) Delete(ctx context.Context, req *mediav1.DeleteRequest) (*mediav1.DeleteResponse, error) {
 	h.log.Infow("Delete request received", "file_path", req.FilePath)
 
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
 	err := h.service.DeleteFile(ctx, req.FilePath)
 	if err != nil {
 		h.log.Errorw("Failed to delete file", "file_path", req.FilePath, "error", err)
@@ -212,20 +247,324 @@ func (h *MediaHandler) Delete(ctx context.Context, req *mediav1.DeleteRequest) (
 
 // GetURL returns the public URL for a file
 func (h *MediaHandler) GetURL(ctx context.Context, req *mediav1.GetURLRequest) (*mediav1.GetURLResponse, error) {
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
 	url := h.service.GetFileURL(req.FilePath)
 	return &mediav1.GetURLResponse{
 		Url: url,
 	}, nil
 }
 
+// PresignUpload returns a presigned URL the client can use to upload a file
+// directly to storage, bypassing the gRPC server for the transfer itself.
+func (h *MediaHandler) PresignUpload(ctx context.Context, req *mediav1.PresignUploadRequest) (*mediav1.PresignUploadResponse, error) {
+	h.log.Infow("PresignUpload request received", "file_name", req.FileName, "content_type", req.ContentType)
+
+	expiry := time.Duration(req.ExpirySeconds) * time.Second
+	filePath, url, err := h.service.PresignUpload(ctx, req.FileName, req.ContentType, expiry)
+	if err != nil {
+		h.log.Errorw("Failed to presign upload", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to presign upload: %v", err)
+	}
+
+	return &mediav1.PresignUploadResponse{
+		FilePath:  filePath,
+		Url:       url,
+		ExpiresAt: time.Now().Add(expiry).Format(time.RFC3339),
+	}, nil
+}
+
+// PresignDownload returns a presigned URL the client can use to download a
+// file directly from storage, optionally overriding the response
+// Content-Disposition/Content-Type so browsers save it under the right name.
+func (h *MediaHandler) PresignDownload(ctx context.Context, req *mediav1.PresignDownloadRequest) (*mediav1.PresignDownloadResponse, error) {
+	h.log.Infow("PresignDownload request received", "file_path", req.FilePath)
+
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	var headers *storage.ResponseHeaderOverrides
+	if req.ResponseContentDisposition != "" || req.ResponseContentType != "" {
+		headers = &storage.ResponseHeaderOverrides{
+			ContentDisposition: req.ResponseContentDisposition,
+			ContentType:        req.ResponseContentType,
+		}
+	}
+
+	expiry := time.Duration(req.ExpirySeconds) * time.Second
+	url, err := h.service.PresignDownload(ctx, req.FilePath, expiry, headers)
+	if err != nil {
+		h.log.Errorw("Failed to presign download", "file_path", req.FilePath, "error", err)
+		return nil, status.Errorf(codes.NotFound, "failed to presign download: %v", err)
+	}
+
+	return &mediav1.PresignDownloadResponse{
+		Url:       url,
+		ExpiresAt: time.Now().Add(expiry).Format(time.RFC3339),
+	}, nil
+}
+
+// InitMultipartUpload starts a resumable multipart upload and returns an
+// upload ID that identifies the session for subsequent UploadPart/ListParts/
+// CompleteMultipartUpload/AbortMultipartUpload calls.
+func (h *MediaHandler) InitMultipartUpload(ctx context.Context, req *mediav1.InitMultipartUploadRequest) (*mediav1.InitMultipartUploadResponse, error) {
+	h.log.Infow("InitMultipartUpload request received", "file_name", req.FileName, "content_type", req.ContentType)
+
+	filePath, uploadID, err := h.service.InitMultipartUpload(ctx, req.FileName, req.ContentType)
+	if err != nil {
+		h.log.Errorw("Failed to init multipart upload", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to init multipart upload: %v", err)
+	}
+
+	return &mediav1.InitMultipartUploadResponse{
+		FilePath: filePath,
+		UploadId: uploadID,
+	}, nil
+}
+
+// UploadPart uploads a single part of a multipart upload.
+func (h *MediaHandler) UploadPart(ctx context.Context, req *mediav1.UploadPartRequest) (*mediav1.UploadPartResponse, error) {
+	h.log.Infow("UploadPart request received",
+		"file_path", req.FilePath,
+		"upload_id", req.UploadId,
+		"part_number", req.PartNumber,
+	)
+
+	if err := h.service.AuthorizeMultipart(ctx, req.UploadId); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	etag, err := h.service.UploadPart(ctx, req.FilePath, req.UploadId, int(req.PartNumber), req.Content)
+	if err != nil {
+		h.log.Errorw("Failed to upload part", "file_path", req.FilePath, "upload_id", req.UploadId, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to upload part: %v", err)
+	}
+
+	return &mediav1.UploadPartResponse{
+		PartNumber: req.PartNumber,
+		Etag:       etag,
+	}, nil
+}
+
+// ListParts returns the parts already received for an in-progress multipart
+// upload, letting a client resume after an interruption.
+func (h *MediaHandler) ListParts(ctx context.Context, req *mediav1.ListPartsRequest) (*mediav1.ListPartsResponse, error) {
+	h.log.Infow("ListParts request received", "file_path", req.FilePath, "upload_id", req.UploadId)
+
+	if err := h.service.AuthorizeMultipart(ctx, req.UploadId); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	parts, err := h.service.ListParts(ctx, req.FilePath, req.UploadId)
+	if err != nil {
+		h.log.Errorw("Failed to list parts", "file_path", req.FilePath, "upload_id", req.UploadId, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to list parts: %v", err)
+	}
+
+	resp := &mediav1.ListPartsResponse{
+		Parts: make([]*mediav1.PartInfo, len(parts)),
+	}
+	for i, p := range parts {
+		resp.Parts[i] = &mediav1.PartInfo{
+			PartNumber: int32(p.PartNumber),
+			Etag:       p.ETag,
+			Size:       p.Size,
+		}
+	}
+
+	return resp, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final
+// object.
+func (h *MediaHandler) CompleteMultipartUpload(ctx context.Context, req *mediav1.CompleteMultipartUploadRequest) (*mediav1.CompleteMultipartUploadResponse, error) {
+	h.log.Infow("CompleteMultipartUpload request received", "file_path", req.FilePath, "upload_id", req.UploadId)
+
+	if err := h.service.AuthorizeMultipart(ctx, req.UploadId); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	parts := make([]storage.MultipartPart, len(req.Parts))
+	for i, p := range req.Parts {
+		parts[i] = storage.MultipartPart{
+			PartNumber: int(p.PartNumber),
+			ETag:       p.Etag,
+		}
+	}
+
+	if err := h.service.CompleteMultipartUpload(ctx, req.FilePath, req.UploadId, parts); err != nil {
+		h.log.Errorw("Failed to complete multipart upload", "file_path", req.FilePath, "upload_id", req.UploadId, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to complete multipart upload: %v", err)
+	}
+
+	return &mediav1.CompleteMultipartUploadResponse{
+		FilePath: req.FilePath,
+		Url:      h.service.GetFileURL(req.FilePath),
+	}, nil
+}
+
+// AbortMultipartUpload cancels an in-progress multipart upload and releases
+// any parts already uploaded.
+func (h *MediaHandler) AbortMultipartUpload(ctx context.Context, req *mediav1.AbortMultipartUploadRequest) (*mediav1.AbortMultipartUploadResponse, error) {
+	h.log.Infow("AbortMultipartUpload request received", "file_path", req.FilePath, "upload_id", req.UploadId)
+
+	if err := h.service.AuthorizeMultipart(ctx, req.UploadId); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	if err := h.service.AbortMultipartUpload(ctx, req.FilePath, req.UploadId); err != nil {
+		h.log.Errorw("Failed to abort multipart upload", "file_path", req.FilePath, "upload_id", req.UploadId, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to abort multipart upload: %v", err)
+	}
+
+	return &mediav1.AbortMultipartUploadResponse{
+		Success: true,
+	}, nil
+}
+
+// GetDerivative returns a named preset rendition (e.g. "thumbnail",
+// "preview") of a previously uploaded image, generating it on first
+// request.
+func (h *MediaHandler) GetDerivative(ctx context.Context, req *mediav1.GetDerivativeRequest) (*mediav1.GetDerivativeResponse, error) {
+	h.log.Infow("GetDerivative request received", "file_path", req.FilePath, "preset", req.Preset)
+
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	reader, contentType, err := h.service.GetDerivative(ctx, req.FilePath, req.Preset)
+	if err != nil {
+		h.log.Errorw("Failed to get derivative", "file_path", req.FilePath, "preset", req.Preset, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to get derivative: %v", err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read derivative content: %v", err)
+	}
+
+	return &mediav1.GetDerivativeResponse{
+		Content:     content,
+		ContentType: contentType,
+	}, nil
+}
+
+// Transform returns an ad-hoc rendition of a previously uploaded image,
+// generating and caching it on first request.
+func (h *MediaHandler) Transform(ctx context.Context, req *mediav1.TransformRequest) (*mediav1.TransformResponse, error) {
+	h.log.Infow("Transform request received", "file_path", req.FilePath, "width", req.Width, "height", req.Height, "format", req.Format)
+
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	reader, contentType, err := h.service.Transform(ctx, req.FilePath, transform.Options{
+		Width:   int(req.Width),
+		Height:  int(req.Height),
+		Format:  req.Format,
+		Quality: int(req.Quality),
+	})
+	if err != nil {
+		h.log.Errorw("Failed to transform image", "file_path", req.FilePath, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to transform image: %v", err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read transformed content: %v", err)
+	}
+
+	return &mediav1.TransformResponse{
+		Content:     content,
+		ContentType: contentType,
+	}, nil
+}
+
 // GetFileInfo retrieves metadata about a file
 func (h *MediaHandler) GetFileInfo(ctx context.Context, req *mediav1.GetFileInfoRequest) (*mediav1.GetFileInfoResponse, error) {
-	// Since our simple storage doesn't store separate metadata, we'll return basic info
-	// In a real app, we might check DB or object storage metadata
-	url := h.service.GetFileURL(req.FilePath)
+	h.log.Infow("GetFileInfo request received", "file_path", req.FilePath)
+
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	info, err := h.service.GetFileInfo(ctx, req.FilePath)
+	if err != nil {
+		h.log.Errorw("Failed to get file info", "file_path", req.FilePath, "error", err)
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
 
 	return &mediav1.GetFileInfoResponse{
+		FilePath:    info.FilePath,
+		FileName:    info.FileName,
+		ContentType: info.ContentType,
+		FileSize:    info.Size,
+		Url:         h.service.GetFileURL(info.FilePath),
+		UploadedAt:  info.UploadedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// VerifyIntegrity re-hashes a previously uploaded file and reports whether
+// it still matches the checksum recorded at upload time.
+func (h *MediaHandler) VerifyIntegrity(ctx context.Context, req *mediav1.VerifyIntegrityRequest) (*mediav1.VerifyIntegrityResponse, error) {
+	h.log.Infow("VerifyIntegrity request received", "file_path", req.FilePath)
+
+	if err := h.service.Authorize(ctx, req.FilePath); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	ok, err := h.service.VerifyIntegrity(ctx, req.FilePath)
+	if err != nil {
+		h.log.Errorw("Failed to verify file integrity", "file_path", req.FilePath, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to verify file integrity: %v", err)
+	}
+
+	return &mediav1.VerifyIntegrityResponse{
 		FilePath: req.FilePath,
-		Url:      url,
+		Valid:    ok,
 	}, nil
 }
+
+// ListFiles returns a page of previously uploaded files, backed by the
+// metadata store since listing objects directly in storage is too slow for
+// user-facing pagination.
+func (h *MediaHandler) ListFiles(ctx context.Context, req *mediav1.ListFilesRequest) (*mediav1.ListFilesResponse, error) {
+	h.log.Infow("ListFiles request received", "uploaded_by", req.UploadedBy, "limit", req.Limit, "offset", req.Offset)
+
+	filter := metadata.Filter{
+		UploadedBy:     req.UploadedBy,
+		FileNamePrefix: req.FileNamePrefix,
+		ContentType:    req.ContentType,
+	}
+	pagination := metadata.Pagination{
+		Limit:  int(req.Limit),
+		Offset: int(req.Offset),
+	}
+
+	files, total, err := h.service.ListFiles(ctx, filter, pagination)
+	if err != nil {
+		h.log.Errorw("Failed to list files", "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to list files: %v", err)
+	}
+
+	resp := &mediav1.ListFilesResponse{
+		Files: make([]*mediav1.FileInfo, len(files)),
+		Total: int32(total),
+	}
+	for i, f := range files {
+		resp.Files[i] = &mediav1.FileInfo{
+			FilePath:    f.FilePath,
+			FileName:    f.FileName,
+			ContentType: f.ContentType,
+			FileSize:    f.Size,
+			Url:         h.service.GetFileURL(f.FilePath),
+			UploadedAt:  f.UploadedAt.Format(time.RFC3339),
+		}
+	}
+	return resp, nil
+}
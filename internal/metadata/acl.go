@@ -0,0 +1,41 @@
+package metadata
+
+import "context"
+
+// ACL is the access-control record for a single object, stored alongside
+// its FileInfo.
+type ACL struct {
+	FilePath     string
+	Owner        string
+	AllowedUsers []string
+	Public       bool
+}
+
+// Allows reports whether subject is permitted to access the object this ACL
+// describes.
+func (a ACL) Allows(subject string) bool {
+	if a.Public {
+		return true
+	}
+	if subject == "" {
+		return false
+	}
+	if subject == a.Owner {
+		return true
+	}
+	for _, u := range a.AllowedUsers {
+		if u == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// ACLStore records and retrieves per-object ACLs. It lives alongside
+// MetadataStore since both are keyed by file path and typically backed by
+// the same database.
+type ACLStore interface {
+	SetACL(ctx context.Context, acl ACL) error
+	GetACL(ctx context.Context, filePath string) (ACL, error)
+	DeleteACL(ctx context.Context, filePath string) error
+}
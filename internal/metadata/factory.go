@@ -0,0 +1,19 @@
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/khdiyz/media-service/internal/config"
+)
+
+// New builds the Store selected by cfg.MetadataBackend.
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.MetadataBackend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(cfg.PostgresDSN)
+	default:
+		return nil, fmt.Errorf("unknown metadata backend: %q", cfg.MetadataBackend)
+	}
+}
@@ -0,0 +1,161 @@
+package metadata
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is an in-memory MetadataStore and ACLStore, useful for tests
+// and for running this service without a Postgres dependency.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	records    map[string]FileInfo
+	acls       map[string]ACL
+	multiparts map[string]PendingMultipart
+}
+
+// NewMemoryStore creates a new in-memory MetadataStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:    make(map[string]FileInfo),
+		acls:       make(map[string]ACL),
+		multiparts: make(map[string]PendingMultipart),
+	}
+}
+
+// PutMultipart creates or replaces the pending-upload record for uploadID.
+func (m *MemoryStore) PutMultipart(ctx context.Context, p PendingMultipart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.multiparts[p.UploadID] = p
+	return nil
+}
+
+// GetMultipart returns the pending-upload record for uploadID, or
+// ErrNotFound.
+func (m *MemoryStore) GetMultipart(ctx context.Context, uploadID string) (PendingMultipart, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.multiparts[uploadID]
+	if !ok {
+		return PendingMultipart{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// DeleteMultipart removes the pending-upload record for uploadID.
+func (m *MemoryStore) DeleteMultipart(ctx context.Context, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.multiparts, uploadID)
+	return nil
+}
+
+// SetACL creates or replaces the ACL for a file.
+func (m *MemoryStore) SetACL(ctx context.Context, acl ACL) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.acls[acl.FilePath] = acl
+	return nil
+}
+
+// GetACL returns the ACL for a file, or ErrNotFound if none is recorded.
+func (m *MemoryStore) GetACL(ctx context.Context, filePath string) (ACL, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	acl, ok := m.acls[filePath]
+	if !ok {
+		return ACL{}, ErrNotFound
+	}
+	return acl, nil
+}
+
+// DeleteACL removes the ACL for a file.
+func (m *MemoryStore) DeleteACL(ctx context.Context, filePath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.acls, filePath)
+	return nil
+}
+
+// Put creates or replaces the metadata record for a file.
+func (m *MemoryStore) Put(ctx context.Context, info FileInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[info.FilePath] = info
+	return nil
+}
+
+// Get returns the metadata record for a file, or ErrNotFound.
+func (m *MemoryStore) Get(ctx context.Context, filePath string) (FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	info, ok := m.records[filePath]
+	if !ok {
+		return FileInfo{}, ErrNotFound
+	}
+	return info, nil
+}
+
+// Delete removes the metadata record for a file.
+func (m *MemoryStore) Delete(ctx context.Context, filePath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, filePath)
+	return nil
+}
+
+// List returns the records matching filter, sorted by upload time
+// descending, along with the total match count.
+func (m *MemoryStore) List(ctx context.Context, filter Filter, pagination Pagination) ([]FileInfo, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []FileInfo
+	for _, info := range m.records {
+		if !matchesFilter(info, filter) {
+			continue
+		}
+		matched = append(matched, info)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].UploadedAt.After(matched[j].UploadedAt)
+	})
+
+	total := len(matched)
+
+	if pagination.Offset >= total {
+		return []FileInfo{}, total, nil
+	}
+	end := total
+	if pagination.Limit > 0 && pagination.Offset+pagination.Limit < end {
+		end = pagination.Offset + pagination.Limit
+	}
+
+	return matched[pagination.Offset:end], total, nil
+}
+
+func matchesFilter(info FileInfo, filter Filter) bool {
+	if filter.UploadedBy != "" && info.UploadedBy != filter.UploadedBy {
+		return false
+	}
+	if filter.ContentType != "" && info.ContentType != filter.ContentType {
+		return false
+	}
+	if filter.FileNamePrefix != "" && !strings.HasPrefix(info.FileName, filter.FileNamePrefix) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStorePutGetDelete(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	info := FileInfo{FilePath: "a/b.jpg", FileName: "b.jpg", ContentType: "image/jpeg", Size: 10, UploadedAt: time.Now()}
+	if err := store.Put(ctx, info); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := store.Get(ctx, info.FilePath)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.FileName != info.FileName {
+		t.Errorf("FileName = %q, want %q", got.FileName, info.FileName)
+	}
+
+	if err := store.Delete(ctx, info.FilePath); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, info.FilePath); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreListFilterAndPaginate(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		store.Put(ctx, FileInfo{
+			FilePath:   "file" + string(rune('a'+i)),
+			FileName:   "file" + string(rune('a'+i)),
+			UploadedBy: "alice",
+			UploadedAt: base.Add(time.Duration(i) * time.Second),
+		})
+	}
+	store.Put(ctx, FileInfo{FilePath: "other", FileName: "other", UploadedBy: "bob", UploadedAt: base})
+
+	results, total, err := store.List(ctx, Filter{UploadedBy: "alice"}, Pagination{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("total = %d, want 5", total)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	// Newest first, so offset 1 skips the most recently uploaded match.
+	if results[0].FilePath != "filed" || results[1].FilePath != "filec" {
+		t.Errorf("results = %v, want [filed filec]", results)
+	}
+}
+
+func TestMemoryStoreACL(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := store.GetACL(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetACL on missing file: err = %v, want ErrNotFound", err)
+	}
+
+	acl := ACL{FilePath: "a", Owner: "alice"}
+	if err := store.SetACL(ctx, acl); err != nil {
+		t.Fatalf("SetACL: %v", err)
+	}
+	got, err := store.GetACL(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetACL: %v", err)
+	}
+	if got.Owner != "alice" {
+		t.Errorf("Owner = %q, want alice", got.Owner)
+	}
+
+	if err := store.DeleteACL(ctx, "a"); err != nil {
+		t.Fatalf("DeleteACL: %v", err)
+	}
+	if _, err := store.GetACL(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetACL after DeleteACL: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreMultipart(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := store.GetMultipart(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetMultipart on missing upload: err = %v, want ErrNotFound", err)
+	}
+
+	p := PendingMultipart{UploadID: "u1", FileName: "big.mp4", ContentType: "video/mp4"}
+	if err := store.PutMultipart(ctx, p); err != nil {
+		t.Fatalf("PutMultipart: %v", err)
+	}
+	got, err := store.GetMultipart(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetMultipart: %v", err)
+	}
+	if got != p {
+		t.Errorf("GetMultipart = %+v, want %+v", got, p)
+	}
+
+	if err := store.DeleteMultipart(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteMultipart: %v", err)
+	}
+	if _, err := store.GetMultipart(ctx, "u1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetMultipart after DeleteMultipart: err = %v, want ErrNotFound", err)
+	}
+}
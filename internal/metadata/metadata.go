@@ -0,0 +1,62 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by MetadataStore.Get when no record exists for the
+// requested file path.
+var ErrNotFound = errors.New("metadata: file not found")
+
+// FileInfo records everything about an uploaded object that object storage
+// itself doesn't reliably preserve or can't list efficiently.
+type FileInfo struct {
+	FilePath    string
+	FileName    string
+	ContentType string
+	Size        int64
+	Checksum    string
+	UploadedBy  string
+	Tags        map[string]string
+	UploadedAt  time.Time
+}
+
+// Filter narrows a ListFiles call. Zero-value fields are ignored.
+type Filter struct {
+	UploadedBy     string
+	FileNamePrefix string
+	ContentType    string
+}
+
+// Pagination limits and offsets a ListFiles call.
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// MetadataStore records and retrieves FileInfo for every object this service manages.
+type MetadataStore interface {
+	// Put creates or replaces the metadata record for a file.
+	Put(ctx context.Context, info FileInfo) error
+
+	// Get returns the metadata record for a file, or ErrNotFound.
+	Get(ctx context.Context, filePath string) (FileInfo, error)
+
+	// Delete removes the metadata record for a file.
+	Delete(ctx context.Context, filePath string) error
+
+	// List returns the records matching filter, along with the total number
+	// of matching records (ignoring pagination) for building page info.
+	List(ctx context.Context, filter Filter, pagination Pagination) ([]FileInfo, int, error)
+}
+
+// Store combines MetadataStore, ACLStore, and MultipartStore, since
+// MemoryStore and PostgresStore back file metadata, its per-object ACL, and
+// pending multipart uploads with the same underlying storage.
+type Store interface {
+	MetadataStore
+	ACLStore
+	MultipartStore
+}
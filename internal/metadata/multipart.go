@@ -0,0 +1,28 @@
+package metadata
+
+import "context"
+
+// PendingMultipart records the fileName/contentType a multipart upload was
+// initiated with, so CompleteMultipartUpload can recover them even if the
+// service restarts between InitMultipartUpload and CompleteMultipartUpload.
+type PendingMultipart struct {
+	UploadID    string
+	FileName    string
+	ContentType string
+	InitiatedBy string
+}
+
+// MultipartStore records and retrieves in-progress multipart upload
+// sessions. It lives alongside MetadataStore since both are typically
+// backed by the same database.
+type MultipartStore interface {
+	// PutMultipart creates or replaces the pending-upload record for uploadID.
+	PutMultipart(ctx context.Context, p PendingMultipart) error
+
+	// GetMultipart returns the pending-upload record for uploadID, or
+	// ErrNotFound.
+	GetMultipart(ctx context.Context, uploadID string) (PendingMultipart, error)
+
+	// DeleteMultipart removes the pending-upload record for uploadID.
+	DeleteMultipart(ctx context.Context, uploadID string) error
+}
@@ -0,0 +1,268 @@
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore is a Postgres-backed MetadataStore.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore opens a Postgres connection and ensures the
+// file_metadata table exists.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	store := &PostgresStore{db: db}
+	if err := store.migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate file_metadata table: %w", err)
+	}
+	return store, nil
+}
+
+func (p *PostgresStore) migrate(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS file_metadata (
+			file_path    TEXT PRIMARY KEY,
+			file_name    TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size         BIGINT NOT NULL,
+			checksum     TEXT NOT NULL DEFAULT '',
+			uploaded_by  TEXT NOT NULL DEFAULT '',
+			tags         JSONB NOT NULL DEFAULT '{}',
+			uploaded_at  TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS file_acls (
+			file_path     TEXT PRIMARY KEY,
+			owner         TEXT NOT NULL DEFAULT '',
+			allowed_users TEXT[] NOT NULL DEFAULT '{}',
+			public        BOOLEAN NOT NULL DEFAULT false
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS pending_multipart_uploads (
+			upload_id    TEXT PRIMARY KEY,
+			file_name    TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			initiated_by TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	return err
+}
+
+// PutMultipart creates or replaces the pending-upload record for uploadID.
+func (p *PostgresStore) PutMultipart(ctx context.Context, m PendingMultipart) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO pending_multipart_uploads (upload_id, file_name, content_type, initiated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (upload_id) DO UPDATE SET
+			file_name = EXCLUDED.file_name,
+			content_type = EXCLUDED.content_type,
+			initiated_by = EXCLUDED.initiated_by
+	`, m.UploadID, m.FileName, m.ContentType, m.InitiatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to upsert pending multipart upload: %w", err)
+	}
+	return nil
+}
+
+// GetMultipart returns the pending-upload record for uploadID, or
+// ErrNotFound.
+func (p *PostgresStore) GetMultipart(ctx context.Context, uploadID string) (PendingMultipart, error) {
+	var m PendingMultipart
+	row := p.db.QueryRowContext(ctx, `SELECT upload_id, file_name, content_type, initiated_by FROM pending_multipart_uploads WHERE upload_id = $1`, uploadID)
+	err := row.Scan(&m.UploadID, &m.FileName, &m.ContentType, &m.InitiatedBy)
+	if err == sql.ErrNoRows {
+		return PendingMultipart{}, ErrNotFound
+	}
+	if err != nil {
+		return PendingMultipart{}, fmt.Errorf("failed to query pending multipart upload: %w", err)
+	}
+	return m, nil
+}
+
+// DeleteMultipart removes the pending-upload record for uploadID.
+func (p *PostgresStore) DeleteMultipart(ctx context.Context, uploadID string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM pending_multipart_uploads WHERE upload_id = $1`, uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending multipart upload: %w", err)
+	}
+	return nil
+}
+
+// SetACL creates or replaces the ACL for a file.
+func (p *PostgresStore) SetACL(ctx context.Context, acl ACL) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO file_acls (file_path, owner, allowed_users, public)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (file_path) DO UPDATE SET
+			owner = EXCLUDED.owner,
+			allowed_users = EXCLUDED.allowed_users,
+			public = EXCLUDED.public
+	`, acl.FilePath, acl.Owner, pq.Array(acl.AllowedUsers), acl.Public)
+	if err != nil {
+		return fmt.Errorf("failed to upsert file acl: %w", err)
+	}
+	return nil
+}
+
+// GetACL returns the ACL for a file, or ErrNotFound.
+func (p *PostgresStore) GetACL(ctx context.Context, filePath string) (ACL, error) {
+	var acl ACL
+	var allowedUsers []string
+	row := p.db.QueryRowContext(ctx, `SELECT file_path, owner, allowed_users, public FROM file_acls WHERE file_path = $1`, filePath)
+	err := row.Scan(&acl.FilePath, &acl.Owner, pq.Array(&allowedUsers), &acl.Public)
+	if err == sql.ErrNoRows {
+		return ACL{}, ErrNotFound
+	}
+	if err != nil {
+		return ACL{}, fmt.Errorf("failed to query file acl: %w", err)
+	}
+	acl.AllowedUsers = allowedUsers
+	return acl, nil
+}
+
+// DeleteACL removes the ACL for a file.
+func (p *PostgresStore) DeleteACL(ctx context.Context, filePath string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM file_acls WHERE file_path = $1`, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to delete file acl: %w", err)
+	}
+	return nil
+}
+
+// Put creates or replaces the metadata record for a file.
+func (p *PostgresStore) Put(ctx context.Context, info FileInfo) error {
+	tags, err := json.Marshal(info.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO file_metadata (file_path, file_name, content_type, size, checksum, uploaded_by, tags, uploaded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (file_path) DO UPDATE SET
+			file_name = EXCLUDED.file_name,
+			content_type = EXCLUDED.content_type,
+			size = EXCLUDED.size,
+			checksum = EXCLUDED.checksum,
+			uploaded_by = EXCLUDED.uploaded_by,
+			tags = EXCLUDED.tags,
+			uploaded_at = EXCLUDED.uploaded_at
+	`, info.FilePath, info.FileName, info.ContentType, info.Size, info.Checksum, info.UploadedBy, tags, info.UploadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert file metadata: %w", err)
+	}
+	return nil
+}
+
+// Get returns the metadata record for a file, or ErrNotFound.
+func (p *PostgresStore) Get(ctx context.Context, filePath string) (FileInfo, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT file_path, file_name, content_type, size, checksum, uploaded_by, tags, uploaded_at
+		FROM file_metadata WHERE file_path = $1
+	`, filePath)
+
+	info, err := scanFileInfo(row)
+	if err == sql.ErrNoRows {
+		return FileInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("failed to query file metadata: %w", err)
+	}
+	return info, nil
+}
+
+// Delete removes the metadata record for a file.
+func (p *PostgresStore) Delete(ctx context.Context, filePath string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM file_metadata WHERE file_path = $1`, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to delete file metadata: %w", err)
+	}
+	return nil
+}
+
+// List returns the records matching filter, newest first, along with the
+// total match count.
+func (p *PostgresStore) List(ctx context.Context, filter Filter, pagination Pagination) ([]FileInfo, int, error) {
+	where := "WHERE ($1 = '' OR uploaded_by = $1) AND ($2 = '' OR content_type = $2) AND ($3 = '' OR file_name LIKE $3 || '%')"
+
+	var total int
+	countRow := p.db.QueryRowContext(ctx, `SELECT count(*) FROM file_metadata `+where,
+		filter.UploadedBy, filter.ContentType, filter.FileNamePrefix)
+	if err := countRow.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count file metadata: %w", err)
+	}
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT file_path, file_name, content_type, size, checksum, uploaded_by, tags, uploaded_at
+		FROM file_metadata `+where+`
+		ORDER BY uploaded_at DESC
+		LIMIT $4 OFFSET $5
+	`, filter.UploadedBy, filter.ContentType, filter.FileNamePrefix, nullIfZero(pagination.Limit), pagination.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list file metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var results []FileInfo
+	for rows.Next() {
+		info, err := scanFileInfo(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan file metadata: %w", err)
+		}
+		results = append(results, info)
+	}
+	return results, total, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFileInfo(row scanner) (FileInfo, error) {
+	var info FileInfo
+	var tags []byte
+
+	err := row.Scan(&info.FilePath, &info.FileName, &info.ContentType, &info.Size, &info.Checksum, &info.UploadedBy, &tags, &info.UploadedAt)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	if len(tags) > 0 {
+		if err := json.Unmarshal(tags, &info.Tags); err != nil {
+			return FileInfo{}, fmt.Errorf("failed to unmarshal tags: %w", err)
+		}
+	}
+	return info, nil
+}
+
+// nullIfZero lets LIMIT 0 mean "no limit" rather than "return nothing".
+func nullIfZero(limit int) any {
+	if limit <= 0 {
+		return nil
+	}
+	return limit
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khdiyz/media-service/internal/auth"
+	"github.com/khdiyz/media-service/internal/metadata"
+)
+
+// ErrForbidden is returned by Authorizer.Authorize when the caller is
+// authenticated but not permitted to access the object.
+var ErrForbidden = errors.New("service: caller is not authorized to access this object")
+
+// Authorizer checks the authenticated caller attached to the request
+// context against the per-object ACL recorded alongside a file's metadata,
+// before Download/Delete/GetURL are allowed to proceed.
+type Authorizer struct {
+	acls metadata.ACLStore
+}
+
+// NewAuthorizer creates a new Authorizer backed by acls.
+func NewAuthorizer(acls metadata.ACLStore) *Authorizer {
+	return &Authorizer{acls: acls}
+}
+
+// Authorize checks whether the identity attached to ctx (by the auth
+// interceptor) is allowed to access filePath. A file with no recorded ACL
+// is denied to every caller; its recorded uploader is not consulted.
+func (a *Authorizer) Authorize(ctx context.Context, filePath string) error {
+	identity, _ := auth.IdentityFromContext(ctx)
+	var subject string
+	if identity != nil {
+		subject = identity.Subject
+	}
+
+	acl, err := a.acls.GetACL(ctx, filePath)
+	if errors.Is(err, metadata.ErrNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+
+	if !acl.Allows(subject) {
+		return ErrForbidden
+	}
+	return nil
+}
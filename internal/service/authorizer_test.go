@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khdiyz/media-service/internal/auth"
+	"github.com/khdiyz/media-service/internal/metadata"
+)
+
+func TestAuthorizerAuthorize(t *testing.T) {
+	ctx := context.Background()
+	acls := metadata.NewMemoryStore()
+	authz := NewAuthorizer(acls)
+
+	acls.SetACL(ctx, metadata.ACL{FilePath: "private.jpg", Owner: "alice"})
+	acls.SetACL(ctx, metadata.ACL{FilePath: "shared.jpg", Owner: "alice", AllowedUsers: []string{"bob"}})
+	acls.SetACL(ctx, metadata.ACL{FilePath: "public.jpg", Public: true})
+
+	asUser := func(subject string) context.Context {
+		if subject == "" {
+			return ctx
+		}
+		return auth.WithIdentity(ctx, &auth.Identity{Subject: subject})
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		filePath string
+		wantErr  error
+	}{
+		{"owner allowed", asUser("alice"), "private.jpg", nil},
+		{"stranger denied", asUser("carol"), "private.jpg", ErrForbidden},
+		{"unauthenticated denied", asUser(""), "private.jpg", ErrForbidden},
+		{"allowed user permitted", asUser("bob"), "shared.jpg", nil},
+		{"public allows anyone", asUser(""), "public.jpg", nil},
+		{"no ACL record denies", asUser("alice"), "missing.jpg", ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authz.Authorize(tt.ctx, tt.filePath)
+			if !errors.Is(err, tt.wantErr) && err != tt.wantErr {
+				t.Errorf("Authorize() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
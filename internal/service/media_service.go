@@ -3,37 +3,129 @@ package service
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/auth"
+	"github.com/khdiyz/media-service/internal/metadata"
 	"github.com/khdiyz/media-service/internal/storage"
+	"github.com/khdiyz/media-service/internal/transform"
 )
 
 // MediaService handles business logic for media operations
 type MediaService struct {
-	storage storage.Storage
-	log     *logger.Logger
+	storage    storage.Storage
+	metadata   metadata.Store
+	authorizer *Authorizer
+	transform  *transform.Service
+	log        *logger.Logger
 }
 
-// NewMediaService creates a new MediaService
-func NewMediaService(storage storage.Storage, log *logger.Logger) *MediaService {
+// NewMediaService creates a new MediaService. authorizer and transformer
+// may both be nil, in which case per-object authorization and derivative
+// generation are simply skipped.
+func NewMediaService(storage storage.Storage, metadataStore metadata.Store, authorizer *Authorizer, transformer *transform.Service, log *logger.Logger) *MediaService {
 	return &MediaService{
-		storage: storage,
-		log:     log,
+		storage:    storage,
+		metadata:   metadataStore,
+		authorizer: authorizer,
+		transform:  transformer,
+		log:        log,
 	}
 }
 
-// UploadFile uploads a file to storage
+// UploadFile uploads a file to storage, records its metadata and ACL, and
+// (for images, when a transform.Service is configured) generates thumbnail
+// and preview derivatives.
 func (s *MediaService) UploadFile(ctx context.Context, fileName string, content []byte, contentType string) (string, error) {
 	reader := bytes.NewReader(content)
 	fileSize := int64(len(content))
 
-	return s.storage.Upload(ctx, fileName, fileSize, reader, contentType)
+	filePath, checksum, err := s.storage.Upload(ctx, fileName, fileSize, reader, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.recordUpload(ctx, filePath, fileName, contentType, fileSize, checksum); err != nil {
+		return "", err
+	}
+
+	if s.transform != nil && transform.IsImage(contentType) {
+		if err := s.transform.GenerateDerivatives(ctx, filePath, content); err != nil {
+			s.log.Errorw("Failed to generate image derivatives", "file_path", filePath, "error", err)
+		}
+	}
+
+	return filePath, nil
 }
 
-// UploadStream uploads a file from a reader (for streaming)
+// UploadStream uploads a file from a reader (for streaming) and records its
+// metadata and ACL.
 func (s *MediaService) UploadStream(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, error) {
-	return s.storage.Upload(ctx, fileName, fileSize, reader, contentType)
+	filePath, checksum, err := s.storage.Upload(ctx, fileName, fileSize, reader, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.recordUpload(ctx, filePath, fileName, contentType, fileSize, checksum); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// recordUpload persists the metadata/ACL for a just-uploaded file. checksum
+// is the digest Upload computed while streaming the bytes; CompleteMultipartUpload
+// has no single Upload call to take it from, so it passes "" and pays for a
+// second download here instead.
+func (s *MediaService) recordUpload(ctx context.Context, filePath, fileName, contentType string, size int64, checksum string) error {
+	if checksum == "" {
+		var err error
+		checksum, err = s.storage.Checksum(ctx, filePath)
+		if err != nil {
+			s.log.Errorw("Failed to compute checksum for uploaded file", "file_path", filePath, "error", err)
+		}
+	}
+
+	var owner string
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		owner = identity.Subject
+	}
+
+	// Content-addressed storage backends can return the same filePath for
+	// distinct uploads of identical bytes. Only write the FileInfo/ACL the
+	// first time a path is seen, so a later uploader who happens to collide
+	// with an existing object can't clobber its recorded owner, filename, or
+	// upload time.
+	if _, err := s.metadata.Get(ctx, filePath); err == nil {
+		return nil
+	} else if err != metadata.ErrNotFound {
+		return err
+	}
+
+	if err := s.metadata.Put(ctx, metadata.FileInfo{
+		FilePath:    filePath,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    checksum,
+		UploadedBy:  owner,
+		UploadedAt:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	return s.metadata.SetACL(ctx, metadata.ACL{FilePath: filePath, Owner: owner})
+}
+
+// Authorize checks whether the caller attached to ctx is allowed to access
+// filePath. It is a no-op when no Authorizer was configured.
+func (s *MediaService) Authorize(ctx context.Context, filePath string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	return s.authorizer.Authorize(ctx, filePath)
 }
 
 // DownloadFile downloads a file from storage
@@ -41,12 +133,201 @@ func (s *MediaService) DownloadFile(ctx context.Context, filePath string) (io.Re
 	return s.storage.Download(ctx, filePath)
 }
 
-// DeleteFile removes a file from storage
+// GetFileInfo returns the recorded metadata for a file.
+func (s *MediaService) GetFileInfo(ctx context.Context, filePath string) (metadata.FileInfo, error) {
+	return s.metadata.Get(ctx, filePath)
+}
+
+// VerifyIntegrity recomputes a file's checksum from storage and compares it
+// against the checksum recorded at upload time, reporting whether the
+// object's bytes are still intact.
+func (s *MediaService) VerifyIntegrity(ctx context.Context, filePath string) (bool, error) {
+	info, err := s.metadata.Get(ctx, filePath)
+	if err != nil {
+		return false, err
+	}
+
+	checksum, err := s.storage.Checksum(ctx, filePath)
+	if err != nil {
+		return false, err
+	}
+
+	return info.Checksum != "" && info.Checksum == checksum, nil
+}
+
+// ListFiles returns a page of recorded file metadata matching filter,
+// dropping any file the caller isn't authorized (per its ACL) to access.
+// ACL filtering has to happen before pagination is applied, otherwise
+// authorized files outside the raw page window would never resurface and
+// the reported total would drift from what the caller can actually see.
+func (s *MediaService) ListFiles(ctx context.Context, filter metadata.Filter, pagination metadata.Pagination) ([]metadata.FileInfo, int, error) {
+	if s.authorizer == nil {
+		return s.metadata.List(ctx, filter, pagination)
+	}
+
+	all, _, err := s.metadata.List(ctx, filter, metadata.Pagination{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	visible := all[:0]
+	for _, f := range all {
+		if err := s.authorizer.Authorize(ctx, f.FilePath); err == nil {
+			visible = append(visible, f)
+		}
+	}
+
+	total := len(visible)
+	if pagination.Offset >= total {
+		return []metadata.FileInfo{}, total, nil
+	}
+	end := total
+	if pagination.Limit > 0 && pagination.Offset+pagination.Limit < end {
+		end = pagination.Offset + pagination.Limit
+	}
+	return visible[pagination.Offset:end], total, nil
+}
+
+// DeleteFile removes a file from storage along with its metadata and ACL.
 func (s *MediaService) DeleteFile(ctx context.Context, filePath string) error {
-	return s.storage.Delete(ctx, filePath)
+	if err := s.storage.Delete(ctx, filePath); err != nil {
+		return err
+	}
+	if err := s.metadata.Delete(ctx, filePath); err != nil {
+		return err
+	}
+	return s.metadata.DeleteACL(ctx, filePath)
 }
 
 // GetFileURL returns the public URL for a file
 func (s *MediaService) GetFileURL(filePath string) string {
 	return s.storage.GetURL(filePath)
 }
+
+// PresignUpload returns a file path the client should upload to and a
+// presigned URL allowing it to upload directly to storage, avoiding the
+// buffering through bytes.NewReader/io.Pipe that UploadFile/UploadStream do.
+func (s *MediaService) PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (string, string, error) {
+	return s.storage.PresignUpload(ctx, fileName, contentType, expiry)
+}
+
+// PresignDownload returns a presigned URL allowing a client to download a
+// file directly from storage. responseHeaders, when non-nil, overrides the
+// Content-Disposition/Content-Type the backend responds with.
+func (s *MediaService) PresignDownload(ctx context.Context, filePath string, expiry time.Duration, responseHeaders *storage.ResponseHeaderOverrides) (string, error) {
+	return s.storage.PresignDownload(ctx, filePath, expiry, responseHeaders)
+}
+
+// InitMultipartUpload starts a resumable multipart upload for a large file.
+// It persists fileName/contentType so CompleteMultipartUpload can record
+// the same metadata/ACL that UploadFile/UploadStream do, even if the
+// service restarts before the client finishes uploading parts.
+func (s *MediaService) InitMultipartUpload(ctx context.Context, fileName, contentType string) (string, string, error) {
+	filePath, uploadID, err := s.storage.InitMultipartUpload(ctx, fileName, contentType)
+	if err != nil {
+		return "", "", err
+	}
+
+	var initiatedBy string
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		initiatedBy = identity.Subject
+	}
+
+	if err := s.metadata.PutMultipart(ctx, metadata.PendingMultipart{
+		UploadID:    uploadID,
+		FileName:    fileName,
+		ContentType: contentType,
+		InitiatedBy: initiatedBy,
+	}); err != nil {
+		return "", "", err
+	}
+
+	return filePath, uploadID, nil
+}
+
+// AuthorizeMultipart checks whether the caller attached to ctx is the one
+// that called InitMultipartUpload for uploadID, so one caller can't upload
+// parts into, inspect, complete, or abort another caller's in-progress
+// upload. It is a no-op when no Authorizer was configured.
+func (s *MediaService) AuthorizeMultipart(ctx context.Context, uploadID string) error {
+	if s.authorizer == nil {
+		return nil
+	}
+
+	pending, err := s.metadata.GetMultipart(ctx, uploadID)
+	if err != nil {
+		if err == metadata.ErrNotFound {
+			return ErrForbidden
+		}
+		return err
+	}
+
+	var subject string
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		subject = identity.Subject
+	}
+	if pending.InitiatedBy != subject {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// UploadPart uploads a single part of a multipart upload.
+func (s *MediaService) UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, content []byte) (string, error) {
+	return s.storage.UploadPart(ctx, filePath, uploadID, partNumber, bytes.NewReader(content), int64(len(content)))
+}
+
+// ListParts returns the parts already received for an in-progress multipart
+// upload, so a client can resume after an interruption.
+func (s *MediaService) ListParts(ctx context.Context, filePath, uploadID string) ([]storage.MultipartPart, error) {
+	return s.storage.ListParts(ctx, filePath, uploadID)
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final
+// object and records its metadata and ACL, the same as UploadFile/
+// UploadStream do for non-multipart uploads.
+func (s *MediaService) CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []storage.MultipartPart) error {
+	if err := s.storage.CompleteMultipartUpload(ctx, filePath, uploadID, parts); err != nil {
+		return err
+	}
+
+	fileName, contentType := filePath, "application/octet-stream"
+	if pending, err := s.metadata.GetMultipart(ctx, uploadID); err == nil {
+		fileName, contentType = pending.FileName, pending.ContentType
+	} else if err != metadata.ErrNotFound {
+		return err
+	}
+	_ = s.metadata.DeleteMultipart(ctx, uploadID)
+
+	var size int64
+	for _, p := range parts {
+		size += p.Size
+	}
+
+	return s.recordUpload(ctx, filePath, fileName, contentType, size, "")
+}
+
+// AbortMultipartUpload cancels an in-progress multipart upload.
+func (s *MediaService) AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error {
+	_ = s.metadata.DeleteMultipart(ctx, uploadID)
+
+	return s.storage.AbortMultipartUpload(ctx, filePath, uploadID)
+}
+
+// GetDerivative returns a named preset rendition of an uploaded image,
+// generating it on first request if image transforms are configured.
+func (s *MediaService) GetDerivative(ctx context.Context, filePath, preset string) (io.ReadCloser, string, error) {
+	if s.transform == nil {
+		return nil, "", fmt.Errorf("image transforms are not configured")
+	}
+	return s.transform.GetDerivative(ctx, filePath, preset)
+}
+
+// Transform returns an ad-hoc rendition of an uploaded image, generating
+// and caching it on first request if image transforms are configured.
+func (s *MediaService) Transform(ctx context.Context, filePath string, opts transform.Options) (io.ReadCloser, string, error) {
+	if s.transform == nil {
+		return nil, "", fmt.Errorf("image transforms are not configured")
+	}
+	return s.transform.Transform(ctx, filePath, opts)
+}
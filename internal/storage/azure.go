@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/config"
+)
+
+// AzureStorage implements the Storage interface using Azure Blob Storage.
+type AzureStorage struct {
+	containerURL  azblob.ContainerURL
+	credential    *azblob.SharedKeyCredential
+	containerName string
+	log           *logger.Logger
+}
+
+// NewAzureStorage creates a new Azure Blob Storage client.
+func NewAzureStorage(cfg *config.Config, log *logger.Logger) (Storage, error) {
+	credential, err := azblob.NewSharedKeyCredential(cfg.AzureAccountName, cfg.AzureAccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure credential: %w", err)
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	containerURL, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net/%s", cfg.AzureAccountName, cfg.AzureContainerName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build azure container url: %w", err)
+	}
+
+	storage := &AzureStorage{
+		containerURL:  azblob.NewContainerURL(*containerURL, pipeline),
+		credential:    credential,
+		containerName: cfg.AzureContainerName,
+		log:           log,
+	}
+
+	ctx := context.Background()
+	if _, err := storage.containerURL.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone); err != nil {
+		if stgErr, ok := err.(azblob.StorageError); !ok || stgErr.ServiceCode() != azblob.ServiceCodeContainerAlreadyExists {
+			return nil, fmt.Errorf("failed to ensure azure container exists: %w", err)
+		}
+	}
+
+	log.Infow("Azure Blob storage initialized successfully", "container", cfg.AzureContainerName)
+	return storage, nil
+}
+
+func (a *AzureStorage) blockBlobURL(blobName string) azblob.BlockBlobURL {
+	return a.containerURL.NewBlockBlobURL(blobName)
+}
+
+// Upload uploads a file to Azure Blob Storage under a content-addressed
+// blob name, so re-uploading identical bytes reuses the existing blob
+// instead of storing a duplicate copy. The digest isn't known until the
+// stream finishes, so the blob is first written to a staging name computed
+// from TeeReader'd bytes, then server-side copied into its final CAS name
+// (or simply discarded, if that name is already occupied).
+func (a *AzureStorage) Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, string, error) {
+	stagingPath := newObjectPath(fileName)
+
+	hasher := sha256.New()
+	_, err := azblob.UploadStreamToBlockBlob(ctx, io.TeeReader(reader, hasher), a.blockBlobURL(stagingPath), azblob.UploadStreamToBlockBlobOptions{
+		BufferSize:      4 * 1024 * 1024,
+		MaxBuffers:      4,
+		BlobHTTPHeaders: azblob.BlobHTTPHeaders{ContentType: contentType},
+	})
+	if err != nil {
+		a.log.Errorw("Failed to upload file to Azure", "staging_path", stagingPath, "error", err)
+		return "", "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	filePath := casPath(checksum, filepath.Ext(fileName))
+
+	if _, err := a.blockBlobURL(filePath).GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{}); err == nil {
+		// Identical content already stored under this CAS name; drop the
+		// redundant staging copy.
+		_, _ = a.blockBlobURL(stagingPath).Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+		a.log.Infow("Deduplicated upload, reusing existing blob",
+			"file_path", filePath,
+			"original_name", fileName,
+			"checksum", checksum,
+		)
+		return filePath, checksum, nil
+	}
+
+	if err := a.copyAndWaitBlob(ctx, filePath, stagingPath); err != nil {
+		a.log.Errorw("Failed to promote staged upload to CAS name", "file_path", filePath, "error", err)
+		return "", "", fmt.Errorf("failed to finalize upload: %w", err)
+	}
+	_, _ = a.blockBlobURL(stagingPath).Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+
+	a.log.Infow("File uploaded successfully",
+		"file_path", filePath,
+		"original_name", fileName,
+		"size", fileSize,
+		"checksum", checksum,
+	)
+
+	return filePath, checksum, nil
+}
+
+// PutAt writes a file directly to filePath, with no content-addressing or
+// dedup, for callers that need a deterministic, caller-chosen key.
+func (a *AzureStorage) PutAt(ctx context.Context, filePath string, reader io.Reader, contentType string) error {
+	_, err := azblob.UploadStreamToBlockBlob(ctx, reader, a.blockBlobURL(filePath), azblob.UploadStreamToBlockBlobOptions{
+		BufferSize:      4 * 1024 * 1024,
+		MaxBuffers:      4,
+		BlobHTTPHeaders: azblob.BlobHTTPHeaders{ContentType: contentType},
+	})
+	if err != nil {
+		a.log.Errorw("Failed to put blob to Azure", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to put blob: %w", err)
+	}
+	return nil
+}
+
+// copyAndWaitBlob server-side copies srcName to dstName within the same
+// container and blocks until Azure reports the (normally near-instant)
+// same-account copy as complete.
+func (a *AzureStorage) copyAndWaitBlob(ctx context.Context, dstName, srcName string) error {
+	resp, err := a.blockBlobURL(dstName).StartCopyFromURL(ctx, a.blockBlobURL(srcName).URL(), azblob.Metadata{}, azblob.ModifiedAccessConditions{}, azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, nil, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to start blob copy: %w", err)
+	}
+
+	status := resp.CopyStatus()
+	for status == azblob.CopyStatusPending {
+		time.Sleep(100 * time.Millisecond)
+		props, err := a.blockBlobURL(dstName).GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to poll blob copy status: %w", err)
+		}
+		status = props.CopyStatus()
+	}
+	if status != azblob.CopyStatusSuccess {
+		return fmt.Errorf("blob copy ended with status %q", status)
+	}
+	return nil
+}
+
+// Download retrieves a file from Azure Blob Storage.
+func (a *AzureStorage) Download(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	resp, err := a.blockBlobURL(filePath).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		a.log.Errorw("Failed to download file from Azure", "file_path", filePath, "error", err)
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+
+	return resp.Body(azblob.RetryReaderOptions{}), nil
+}
+
+// Delete removes a file from Azure Blob Storage.
+func (a *AzureStorage) Delete(ctx context.Context, filePath string) error {
+	_, err := a.blockBlobURL(filePath).Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+	if err != nil {
+		a.log.Errorw("Failed to delete file from Azure", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetURL returns the public URL for accessing a file.
+func (a *AzureStorage) GetURL(filePath string) string {
+	return a.blockBlobURL(filePath).URL().String()
+}
+
+// PresignUpload returns a new blob path and a SAS URL that allows a client
+// to upload directly to Azure Blob Storage.
+func (a *AzureStorage) PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (string, string, error) {
+	filePath := newObjectPath(fileName)
+
+	sasURL, err := a.signedURL(filePath, expiry, azblob.BlobSASPermissions{Create: true, Write: true})
+	if err != nil {
+		return "", "", err
+	}
+	return filePath, sasURL, nil
+}
+
+// PresignDownload returns a SAS URL that allows a client to download a file
+// directly from Azure Blob Storage.
+func (a *AzureStorage) PresignDownload(ctx context.Context, filePath string, expiry time.Duration, headers *ResponseHeaderOverrides) (string, error) {
+	sasURL, err := a.signedURL(filePath, expiry, azblob.BlobSASPermissions{Read: true})
+	if err != nil {
+		return "", err
+	}
+
+	if headers == nil {
+		return sasURL, nil
+	}
+
+	u, err := url.Parse(sasURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse sas url: %w", err)
+	}
+	q := u.Query()
+	if headers.ContentDisposition != "" {
+		q.Set("rscd", headers.ContentDisposition)
+	}
+	if headers.ContentType != "" {
+		q.Set("rsct", headers.ContentType)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (a *AzureStorage) signedURL(blobName string, expiry time.Duration, perms azblob.BlobSASPermissions) (string, error) {
+	sas, err := azblob.BlobSASSignatureValues{
+		Protocol:      azblob.SASProtocolHTTPS,
+		ExpiryTime:    time.Now().Add(expiry),
+		ContainerName: a.containerName,
+		BlobName:      blobName,
+		Permissions:   perms.String(),
+	}.NewSASQueryParameters(a.credential)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign blob url: %w", err)
+	}
+
+	blobURL := a.blockBlobURL(blobName).URL()
+	blobURL.RawQuery = sas.Encode()
+	return blobURL.String(), nil
+}
+
+// InitMultipartUpload stages a block blob for a multipart (block-list)
+// upload. Azure has no separate upload-session ID; the block blob name
+// itself is used as the upload ID.
+func (a *AzureStorage) InitMultipartUpload(ctx context.Context, fileName, contentType string) (string, string, error) {
+	filePath := newObjectPath(fileName)
+	return filePath, filePath, nil
+}
+
+// UploadPart stages a block and returns its block ID, which must be supplied
+// to CompleteMultipartUpload in the desired order.
+func (a *AzureStorage) UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, reader io.Reader, partSize int64) (string, error) {
+	blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", partNumber)))
+
+	_, err := a.blockBlobURL(filePath).StageBlock(ctx, blockID, reader, azblob.LeaseAccessConditions{}, nil, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to stage block %d: %w", partNumber, err)
+	}
+	return blockID, nil
+}
+
+// ListParts returns the blocks staged so far for an in-progress block blob
+// upload.
+func (a *AzureStorage) ListParts(ctx context.Context, filePath, uploadID string) ([]MultipartPart, error) {
+	resp, err := a.blockBlobURL(filePath).GetBlockList(ctx, azblob.BlockListUncommitted, azblob.LeaseAccessConditions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged blocks: %w", err)
+	}
+
+	parts := make([]MultipartPart, len(resp.UncommittedBlocks))
+	for i, b := range resp.UncommittedBlocks {
+		partNumber, err := blockIDToPartNumber(b.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode block id %q: %w", b.Name, err)
+		}
+		parts[i] = MultipartPart{PartNumber: partNumber, ETag: b.Name, Size: b.Size}
+	}
+	return parts, nil
+}
+
+// blockIDToPartNumber recovers the part number UploadPart encoded into the
+// block ID, since GetBlockList doesn't guarantee UncommittedBlocks comes
+// back in upload order.
+func blockIDToPartNumber(blockID string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(blockID)
+	if err != nil {
+		return 0, err
+	}
+
+	var partNumber int
+	if _, err := fmt.Sscanf(string(decoded), "%010d", &partNumber); err != nil {
+		return 0, err
+	}
+	return partNumber, nil
+}
+
+// CompleteMultipartUpload commits the staged blocks, in order, into the
+// final blob.
+func (a *AzureStorage) CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []MultipartPart) error {
+	blockIDs := make([]string, len(parts))
+	for i, p := range parts {
+		blockIDs[i] = p.ETag
+	}
+
+	_, err := a.blockBlobURL(filePath).CommitBlockList(ctx, blockIDs, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, nil, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to commit block list: %w", err)
+	}
+	return nil
+}
+
+// AbortMultipartUpload does nothing: there is no explicit abort API, so
+// staged-but-uncommitted blocks are left for Azure to garbage-collect
+// automatically after ~7 days.
+func (a *AzureStorage) AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error {
+	return nil
+}
+
+// Checksum re-hashes a blob's current contents.
+func (a *AzureStorage) Checksum(ctx context.Context, filePath string) (string, error) {
+	return checksumViaDownload(ctx, a, filePath)
+}
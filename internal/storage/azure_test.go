@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestBlockIDToPartNumber(t *testing.T) {
+	for _, partNumber := range []int{1, 2, 42, 9999} {
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", partNumber)))
+
+		got, err := blockIDToPartNumber(blockID)
+		if err != nil {
+			t.Fatalf("blockIDToPartNumber(%q): %v", blockID, err)
+		}
+		if got != partNumber {
+			t.Errorf("blockIDToPartNumber(%q) = %d, want %d", blockID, got, partNumber)
+		}
+	}
+}
+
+func TestBlockIDToPartNumberInvalid(t *testing.T) {
+	if _, err := blockIDToPartNumber("not-valid-base64!!"); err == nil {
+		t.Error("blockIDToPartNumber with invalid base64: want error, got nil")
+	}
+}
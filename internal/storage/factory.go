@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/config"
+)
+
+// New builds the Storage backend selected by cfg.StorageBackend. Every
+// backend implements the same Storage interface, so callers (MediaService)
+// never need to know which one is wired up.
+func New(cfg *config.Config, log *logger.Logger) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "", "minio":
+		return NewMinioStorage(cfg, log)
+	case "azure":
+		return NewAzureStorage(cfg, log)
+	case "gcs":
+		return NewGCSStorage(cfg, log)
+	case "fs":
+		return NewLocalStorage(cfg, log)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %q", cfg.StorageBackend)
+	}
+}
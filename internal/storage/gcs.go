@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/config"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSStorage implements the Storage interface using Google Cloud Storage.
+type GCSStorage struct {
+	client     *storage.Client
+	bucket     *storage.BucketHandle
+	bucketName string
+	log        *logger.Logger
+}
+
+// NewGCSStorage creates a new Google Cloud Storage client.
+func NewGCSStorage(cfg *config.Config, log *logger.Logger) (Storage, error) {
+	ctx := context.Background()
+
+	var opts []option.ClientOption
+	if cfg.GCSCredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.GCSCredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+
+	log.Infow("GCS storage initialized successfully", "bucket", cfg.GCSBucketName)
+	return &GCSStorage{
+		client:     client,
+		bucket:     client.Bucket(cfg.GCSBucketName),
+		bucketName: cfg.GCSBucketName,
+		log:        log,
+	}, nil
+}
+
+// Upload uploads a file to Google Cloud Storage under a content-addressed
+// object name, so re-uploading identical bytes reuses the existing object
+// instead of storing a duplicate copy. The digest isn't known until the
+// stream finishes, so the object is first written to a staging name
+// computed from TeeReader'd bytes, then server-side composed into its final
+// CAS name (or simply discarded, if that name is already occupied).
+func (g *GCSStorage) Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, string, error) {
+	stagingPath := newObjectPath(fileName)
+
+	hasher := sha256.New()
+	w := g.bucket.Object(stagingPath).NewWriter(ctx)
+	w.ContentType = contentType
+
+	if _, err := io.Copy(w, io.TeeReader(reader, hasher)); err != nil {
+		w.Close()
+		g.log.Errorw("Failed to upload file to GCS", "staging_path", stagingPath, "error", err)
+		return "", "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		g.log.Errorw("Failed to finalize GCS upload", "staging_path", stagingPath, "error", err)
+		return "", "", fmt.Errorf("failed to finalize upload: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	filePath := casPath(checksum, filepath.Ext(fileName))
+
+	if _, err := g.bucket.Object(filePath).Attrs(ctx); err == nil {
+		// Identical content already stored under this CAS name; drop the
+		// redundant staging copy.
+		_ = g.bucket.Object(stagingPath).Delete(ctx)
+		g.log.Infow("Deduplicated upload, reusing existing object",
+			"file_path", filePath,
+			"original_name", fileName,
+			"checksum", checksum,
+		)
+		return filePath, checksum, nil
+	}
+
+	if _, err := g.bucket.Object(filePath).CopierFrom(g.bucket.Object(stagingPath)).Run(ctx); err != nil {
+		g.log.Errorw("Failed to promote staged upload to CAS name", "file_path", filePath, "error", err)
+		return "", "", fmt.Errorf("failed to finalize upload: %w", err)
+	}
+	_ = g.bucket.Object(stagingPath).Delete(ctx)
+
+	g.log.Infow("File uploaded successfully",
+		"file_path", filePath,
+		"original_name", fileName,
+		"size", fileSize,
+		"checksum", checksum,
+	)
+
+	return filePath, checksum, nil
+}
+
+// PutAt writes a file directly to filePath, with no content-addressing or
+// dedup, for callers that need a deterministic, caller-chosen key.
+func (g *GCSStorage) PutAt(ctx context.Context, filePath string, reader io.Reader, contentType string) error {
+	w := g.bucket.Object(filePath).NewWriter(ctx)
+	w.ContentType = contentType
+
+	if _, err := io.Copy(w, reader); err != nil {
+		w.Close()
+		g.log.Errorw("Failed to put object to GCS", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		g.log.Errorw("Failed to finalize GCS put", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to finalize put: %w", err)
+	}
+	return nil
+}
+
+// Download retrieves a file from Google Cloud Storage.
+func (g *GCSStorage) Download(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	r, err := g.bucket.Object(filePath).NewReader(ctx)
+	if err != nil {
+		g.log.Errorw("Failed to download file from GCS", "file_path", filePath, "error", err)
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return r, nil
+}
+
+// Delete removes a file from Google Cloud Storage.
+func (g *GCSStorage) Delete(ctx context.Context, filePath string) error {
+	if err := g.bucket.Object(filePath).Delete(ctx); err != nil {
+		g.log.Errorw("Failed to delete file from GCS", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetURL returns the public URL for accessing a file.
+func (g *GCSStorage) GetURL(filePath string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.bucketName, filePath)
+}
+
+// PresignUpload returns a new object path and a V4 signed PUT URL that lets
+// a client upload directly to GCS.
+func (g *GCSStorage) PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (string, string, error) {
+	filePath := newObjectPath(fileName)
+
+	signedURL, err := g.client.Bucket(g.bucketName).SignedURL(filePath, &storage.SignedURLOptions{
+		Method:      "PUT",
+		Expires:     time.Now().Add(expiry),
+		ContentType: contentType,
+		Scheme:      storage.SigningSchemeV4,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign upload url: %w", err)
+	}
+	return filePath, signedURL, nil
+}
+
+// PresignDownload returns a V4 signed GET URL that lets a client download a
+// file directly from GCS, optionally overriding response headers.
+func (g *GCSStorage) PresignDownload(ctx context.Context, filePath string, expiry time.Duration, headers *ResponseHeaderOverrides) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(expiry),
+		Scheme:  storage.SigningSchemeV4,
+	}
+	if headers != nil {
+		if headers.ContentDisposition != "" {
+			opts.QueryParameters = map[string][]string{"response-content-disposition": {headers.ContentDisposition}}
+		}
+		if headers.ContentType != "" {
+			opts.ResponseContentType = headers.ContentType
+		}
+	}
+
+	signedURL, err := g.client.Bucket(g.bucketName).SignedURL(filePath, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign download url: %w", err)
+	}
+	return signedURL, nil
+}
+
+// InitMultipartUpload reserves an object path. GCS has no native multipart
+// API; parts are staged as temporary objects and assembled with Compose.
+func (g *GCSStorage) InitMultipartUpload(ctx context.Context, fileName, contentType string) (string, string, error) {
+	filePath := newObjectPath(fileName)
+	uploadID := fmt.Sprintf("%s.upload", filePath)
+	return filePath, uploadID, nil
+}
+
+func (g *GCSStorage) partObjectName(uploadID string, partNumber int) string {
+	return fmt.Sprintf("%s/parts/%d", uploadID, partNumber)
+}
+
+// UploadPart stores a part as a temporary object to be composed later.
+func (g *GCSStorage) UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, reader io.Reader, partSize int64) (string, error) {
+	w := g.bucket.Object(g.partObjectName(uploadID, partNumber)).NewWriter(ctx)
+	if _, err := io.Copy(w, reader); err != nil {
+		w.Close()
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize part %d: %w", partNumber, err)
+	}
+	return g.partObjectName(uploadID, partNumber), nil
+}
+
+// ListParts lists the temporary part objects staged for an in-progress
+// upload.
+func (g *GCSStorage) ListParts(ctx context.Context, filePath, uploadID string) ([]MultipartPart, error) {
+	it := g.bucket.Objects(ctx, &storage.Query{Prefix: fmt.Sprintf("%s/parts/", uploadID)})
+	var parts []MultipartPart
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			break
+		}
+		var partNumber int
+		fmt.Sscanf(attrs.Name, fmt.Sprintf("%s/parts/%%d", uploadID), &partNumber)
+		parts = append(parts, MultipartPart{PartNumber: partNumber, ETag: attrs.Name, Size: attrs.Size})
+	}
+	return parts, nil
+}
+
+// gcsComposeLimit is the maximum number of source objects GCS's compose API
+// accepts in a single call.
+const gcsComposeLimit = 32
+
+// CompleteMultipartUpload composes the staged part objects, in order, into
+// the final object and cleans up the temporary parts. Uploads with more
+// parts than the GCS compose API accepts in one call are composed in
+// batches, recursively composing intermediate objects until one final
+// compose produces filePath.
+func (g *GCSStorage) CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []MultipartPart) error {
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = p.ETag
+	}
+
+	intermediates, err := g.composeInBatches(ctx, filePath, names, 0)
+	if err != nil {
+		return fmt.Errorf("failed to compose multipart upload: %w", err)
+	}
+
+	for _, p := range parts {
+		_ = g.bucket.Object(p.ETag).Delete(ctx)
+	}
+	for _, name := range intermediates {
+		_ = g.bucket.Object(name).Delete(ctx)
+	}
+	return nil
+}
+
+// composeInBatches composes the objects named by srcs, in order, into
+// dstName, recursively composing batches of at most gcsComposeLimit objects
+// at a time when there are too many to compose in a single call. level
+// namespaces the intermediate objects created at each recursion depth so
+// they don't collide with intermediates from other levels. It returns the
+// names of any intermediate objects it created along the way, so the caller
+// can clean them up.
+func (g *GCSStorage) composeInBatches(ctx context.Context, dstName string, srcs []string, level int) ([]string, error) {
+	if len(srcs) <= gcsComposeLimit {
+		handles := make([]*storage.ObjectHandle, len(srcs))
+		for i, name := range srcs {
+			handles[i] = g.bucket.Object(name)
+		}
+		if _, err := g.bucket.Object(dstName).ComposerFrom(handles...).Run(ctx); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	var intermediates []string
+	var batchNames []string
+	for i := 0; i < len(srcs); i += gcsComposeLimit {
+		end := i + gcsComposeLimit
+		if end > len(srcs) {
+			end = len(srcs)
+		}
+		batch := srcs[i:end]
+
+		batchName := fmt.Sprintf("%s.compose.%d.%d", dstName, level, i/gcsComposeLimit)
+		handles := make([]*storage.ObjectHandle, len(batch))
+		for j, name := range batch {
+			handles[j] = g.bucket.Object(name)
+		}
+		if _, err := g.bucket.Object(batchName).ComposerFrom(handles...).Run(ctx); err != nil {
+			return intermediates, err
+		}
+		intermediates = append(intermediates, batchName)
+		batchNames = append(batchNames, batchName)
+	}
+
+	nested, err := g.composeInBatches(ctx, dstName, batchNames, level+1)
+	intermediates = append(intermediates, nested...)
+	return intermediates, err
+}
+
+// AbortMultipartUpload deletes any temporary part objects staged for an
+// upload.
+func (g *GCSStorage) AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error {
+	parts, err := g.ListParts(ctx, filePath, uploadID)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		_ = g.bucket.Object(p.ETag).Delete(ctx)
+	}
+	return nil
+}
+
+// Checksum re-hashes an object's current contents.
+func (g *GCSStorage) Checksum(ctx context.Context, filePath string) (string, error) {
+	return checksumViaDownload(ctx, g, filePath)
+}
@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/config"
+)
+
+// LocalStorage implements the Storage interface on top of the local
+// filesystem, for development and test environments that don't have a MinIO
+// (or other object store) instance available.
+type LocalStorage struct {
+	basePath string
+	baseURL  string
+	log      *logger.Logger
+
+	mu           sync.Mutex
+	multipartDir string
+}
+
+// NewLocalStorage creates a new filesystem-backed storage client rooted at
+// cfg.LocalBasePath.
+func NewLocalStorage(cfg *config.Config, log *logger.Logger) (Storage, error) {
+	if err := os.MkdirAll(cfg.LocalBasePath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage base path: %w", err)
+	}
+
+	multipartDir := filepath.Join(cfg.LocalBasePath, ".multipart")
+	if err := os.MkdirAll(multipartDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local multipart staging dir: %w", err)
+	}
+
+	log.Infow("Local filesystem storage initialized successfully", "base_path", cfg.LocalBasePath)
+	return &LocalStorage{
+		basePath:     cfg.LocalBasePath,
+		baseURL:      cfg.LocalBaseURL,
+		log:          log,
+		multipartDir: multipartDir,
+	}, nil
+}
+
+// absPath resolves filePath (client-supplied, so untrusted) against
+// basePath, rejecting anything that would escape the storage root via
+// ".." segments. An absolute filePath is resolved under basePath rather
+// than rejected.
+func (l *LocalStorage) absPath(filePath string) (string, error) {
+	base := filepath.Clean(l.basePath)
+	full := filepath.Join(base, filepath.FromSlash(filePath))
+	if full != base && !strings.HasPrefix(full, base+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid file path %q: escapes storage root", filePath)
+	}
+	return full, nil
+}
+
+// Upload writes a file to the local filesystem under a content-addressed
+// path, so re-uploading identical bytes reuses the existing file instead of
+// storing a duplicate copy. The digest isn't known until the file is fully
+// written, so it's first written to a staging path computed from
+// TeeReader'd bytes, then renamed into its final CAS path (or simply
+// discarded, if that path is already occupied by an identical upload).
+func (l *LocalStorage) Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, string, error) {
+	stagingPath := newObjectPath(fileName)
+	stagingDest, err := l.absPath(stagingPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(stagingDest), 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create destination dir: %w", err)
+	}
+
+	f, err := os.Create(stagingDest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create file: %w", err)
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(f, io.TeeReader(reader, hasher))
+	f.Close()
+	if err != nil {
+		l.log.Errorw("Failed to write file to local storage", "staging_path", stagingPath, "error", err)
+		return "", "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	filePath := casPath(checksum, filepath.Ext(fileName))
+	dest, err := l.absPath(filePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := os.Stat(dest); err == nil {
+		// Identical content already stored under this CAS path; drop the
+		// redundant staging copy.
+		_ = os.Remove(stagingDest)
+		l.log.Infow("Deduplicated upload, reusing existing file",
+			"file_path", filePath,
+			"original_name", fileName,
+			"checksum", checksum,
+		)
+		return filePath, checksum, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create destination dir: %w", err)
+	}
+	if err := os.Rename(stagingDest, dest); err != nil {
+		l.log.Errorw("Failed to promote staged upload to CAS path", "file_path", filePath, "error", err)
+		return "", "", fmt.Errorf("failed to finalize upload: %w", err)
+	}
+
+	l.log.Infow("File uploaded successfully",
+		"file_path", filePath,
+		"original_name", fileName,
+		"size", fileSize,
+		"checksum", checksum,
+	)
+
+	return filePath, checksum, nil
+}
+
+// PutAt writes a file directly to filePath, with no content-addressing or
+// dedup, for callers that need a deterministic, caller-chosen key.
+func (l *LocalStorage) PutAt(ctx context.Context, filePath string, reader io.Reader, contentType string) error {
+	dest, err := l.absPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create destination dir: %w", err)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, reader); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Download opens a file from the local filesystem.
+func (l *LocalStorage) Download(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	abs, err := l.absPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(abs)
+	if err != nil {
+		l.log.Errorw("File not found in local storage", "file_path", filePath, "error", err)
+		return nil, fmt.Errorf("file not found: %w", err)
+	}
+	return f, nil
+}
+
+// Delete removes a file from the local filesystem.
+func (l *LocalStorage) Delete(ctx context.Context, filePath string) error {
+	abs, err := l.absPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(abs); err != nil {
+		l.log.Errorw("Failed to delete file from local storage", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetURL returns the URL the dev server (or a reverse proxy serving
+// LocalBasePath) exposes a file under.
+func (l *LocalStorage) GetURL(filePath string) string {
+	if l.baseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", l.baseURL, filePath)
+}
+
+// PresignUpload has no real credential boundary to hand out on a local
+// filesystem, so it just reserves an object path and returns the regular
+// upload URL; the expiry is cosmetic and kept for interface parity with the
+// other backends.
+func (l *LocalStorage) PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (string, string, error) {
+	filePath := newObjectPath(fileName)
+	return filePath, l.GetURL(filePath), nil
+}
+
+// PresignDownload returns the regular download URL; response header
+// overrides aren't meaningful without a real presigning backend.
+func (l *LocalStorage) PresignDownload(ctx context.Context, filePath string, expiry time.Duration, headers *ResponseHeaderOverrides) (string, error) {
+	abs, err := l.absPath(filePath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", fmt.Errorf("file not found: %w", err)
+	}
+	return l.GetURL(filePath), nil
+}
+
+// InitMultipartUpload reserves an object path and stages parts under a
+// per-upload directory until CompleteMultipartUpload assembles them.
+func (l *LocalStorage) InitMultipartUpload(ctx context.Context, fileName, contentType string) (string, string, error) {
+	filePath := newObjectPath(fileName)
+	uploadID := url.QueryEscape(fmt.Sprintf("%s-%s", filePath, fileName))
+
+	if err := os.MkdirAll(filepath.Join(l.multipartDir, uploadID), 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to stage multipart upload: %w", err)
+	}
+
+	return filePath, uploadID, nil
+}
+
+// UploadPart stages a single part on disk.
+func (l *LocalStorage) UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, reader io.Reader, partSize int64) (string, error) {
+	partPath := filepath.Join(l.multipartDir, uploadID, fmt.Sprintf("%d", partNumber))
+
+	f, err := os.Create(partPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stage part %d: %w", partNumber, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, reader); err != nil {
+		return "", fmt.Errorf("failed to write part %d: %w", partNumber, err)
+	}
+
+	// The local backend has no real ETag; the part number, which is also the
+	// staged part's file name, is returned instead, and
+	// CompleteMultipartUpload finds the part again by its number.
+	return fmt.Sprintf("%d", partNumber), nil
+}
+
+// ListParts returns the parts already staged on disk for an in-progress
+// multipart upload.
+func (l *LocalStorage) ListParts(ctx context.Context, filePath, uploadID string) ([]MultipartPart, error) {
+	entries, err := os.ReadDir(filepath.Join(l.multipartDir, uploadID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged parts: %w", err)
+	}
+
+	parts := make([]MultipartPart, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		var partNumber int
+		fmt.Sscanf(e.Name(), "%d", &partNumber)
+		parts = append(parts, MultipartPart{PartNumber: partNumber, ETag: e.Name(), Size: info.Size()})
+	}
+	return parts, nil
+}
+
+// CompleteMultipartUpload concatenates the staged parts, in order, into the
+// final object and removes the staging directory.
+func (l *LocalStorage) CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []MultipartPart) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	dest, err := l.absPath(filePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create destination dir: %w", err)
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create assembled file: %w", err)
+	}
+	defer out.Close()
+
+	stageDir := filepath.Join(l.multipartDir, uploadID)
+	for _, p := range parts {
+		in, err := os.Open(filepath.Join(stageDir, fmt.Sprintf("%d", p.PartNumber)))
+		if err != nil {
+			return fmt.Errorf("failed to open staged part %d: %w", p.PartNumber, err)
+		}
+		_, err = io.Copy(out, in)
+		in.Close()
+		if err != nil {
+			return fmt.Errorf("failed to assemble part %d: %w", p.PartNumber, err)
+		}
+	}
+
+	return os.RemoveAll(stageDir)
+}
+
+// AbortMultipartUpload discards any staged parts for an in-progress
+// multipart upload.
+func (l *LocalStorage) AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error {
+	return os.RemoveAll(filepath.Join(l.multipartDir, uploadID))
+}
+
+// Checksum re-hashes a file's current contents.
+func (l *LocalStorage) Checksum(ctx context.Context, filePath string) (string, error) {
+	return checksumViaDownload(ctx, l, filePath)
+}
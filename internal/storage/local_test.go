@@ -0,0 +1,46 @@
+package storage
+
+import "testing"
+
+func TestLocalStorageAbsPathRejectsTraversal(t *testing.T) {
+	l := &LocalStorage{basePath: "/data/media"}
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantErr  bool
+	}{
+		{"plain path", "2024/01/01/file.jpg", false},
+		{"dot dot traversal", "../../etc/passwd", true},
+		{"embedded traversal", "2024/../../etc/passwd", true},
+		{"absolute path", "/etc/passwd", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.absPath(tt.filePath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("absPath(%q) = %q, want error", tt.filePath, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("absPath(%q): unexpected error: %v", tt.filePath, err)
+			}
+		})
+	}
+}
+
+func TestLocalStorageAbsPathStaysWithinBase(t *testing.T) {
+	l := &LocalStorage{basePath: "/data/media"}
+
+	got, err := l.absPath("2024/01/01/file.jpg")
+	if err != nil {
+		t.Fatalf("absPath: %v", err)
+	}
+	want := "/data/media/2024/01/01/file.jpg"
+	if got != want {
+		t.Errorf("absPath = %q, want %q", got, want)
+	}
+}
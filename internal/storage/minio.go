@@ -2,8 +2,11 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/url"
 	"path/filepath"
 	"time"
 
@@ -17,6 +20,7 @@ import (
 // MinioStorage implements the Storage interface using MinIO
 type MinioStorage struct {
 	client     *minio.Client
+	core       *minio.Core
 	bucketName string
 	fileURL    string
 	log        *logger.Logger
@@ -33,8 +37,20 @@ func NewMinioStorage(cfg *config.Config, log *logger.Logger) (Storage, error) {
 		return nil, fmt.Errorf("failed to create minio client: %w", err)
 	}
 
+	// The core client exposes the lower-level multipart primitives
+	// (NewMultipartUpload/PutObjectPart/...) that minio.Client hides behind
+	// its single-shot PutObject helper.
+	core, err := minio.NewCore(cfg.MinioEndpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.MinioAccessKey, cfg.MinioSecretKey, ""),
+		Secure: cfg.MinioUseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio core client: %w", err)
+	}
+
 	storage := &MinioStorage{
 		client:     client,
+		core:       core,
 		bucketName: cfg.MinioBucketName,
 		fileURL:    cfg.MinioFileUrl,
 		log:        log,
@@ -67,33 +83,94 @@ func (m *MinioStorage) ensureBucket(ctx context.Context) error {
 	return nil
 }
 
-// Upload uploads a file to MinIO storage
-func (m *MinioStorage) Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, error) {
-	// Generate unique file path with UUID to avoid collisions
+// newObjectPath generates a unique object key for fileName, namespaced by
+// upload date to keep buckets browsable.
+func newObjectPath(fileName string) string {
 	ext := filepath.Ext(fileName)
 	uniqueID := uuid.NewString()
 	timestamp := time.Now().Format("2006/01/02")
-	filePath := fmt.Sprintf("%s/%s%s", timestamp, uniqueID, ext)
+	return fmt.Sprintf("%s/%s%s", timestamp, uniqueID, ext)
+}
+
+// casPath builds the content-addressed key an object with the given SHA-256
+// digest and original extension is stored under, e.g.
+// "sha256/aa/bb/aabb...<hex>.jpg".
+func casPath(checksum, ext string) string {
+	return fmt.Sprintf("sha256/%s/%s/%s%s", checksum[0:2], checksum[2:4], checksum, ext)
+}
 
-	// Upload file to MinIO
-	_, err := m.client.PutObject(ctx, m.bucketName, filePath, reader, fileSize, minio.PutObjectOptions{
+// Upload uploads a file to MinIO storage under a content-addressed key, so
+// re-uploading identical bytes (common for media assets) reuses the
+// existing object instead of storing a duplicate copy.
+//
+// The digest isn't known until the upload finishes streaming, so the file
+// is first written to a staging key computed from TeeReader'd bytes, then
+// server-side copied into its final CAS key (or simply discarded, if that
+// key is already occupied by an identical upload).
+func (m *MinioStorage) Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, string, error) {
+	stagingPath := newObjectPath(fileName)
+
+	hasher := sha256.New()
+	_, err := m.client.PutObject(ctx, m.bucketName, stagingPath, io.TeeReader(reader, hasher), fileSize, minio.PutObjectOptions{
 		ContentType: contentType,
 	})
 	if err != nil {
-		m.log.Errorw("Failed to upload file to MinIO",
+		m.log.Errorw("Failed to upload file to MinIO", "staging_path", stagingPath, "error", err)
+		return "", "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	filePath := casPath(checksum, filepath.Ext(fileName))
+
+	if _, err := m.client.StatObject(ctx, m.bucketName, filePath, minio.StatObjectOptions{}); err == nil {
+		// Identical content already stored under this CAS key; drop the
+		// redundant staging copy.
+		_ = m.client.RemoveObject(ctx, m.bucketName, stagingPath, minio.RemoveObjectOptions{})
+		m.log.Infow("Deduplicated upload, reusing existing object",
 			"file_path", filePath,
-			"error", err,
+			"original_name", fileName,
+			"checksum", checksum,
 		)
-		return "", fmt.Errorf("failed to upload file: %w", err)
+		return filePath, checksum, nil
+	}
+
+	_, err = m.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: m.bucketName, Object: filePath},
+		minio.CopySrcOptions{Bucket: m.bucketName, Object: stagingPath},
+	)
+	if err != nil {
+		m.log.Errorw("Failed to promote staged upload to CAS key", "file_path", filePath, "error", err)
+		return "", "", fmt.Errorf("failed to finalize upload: %w", err)
 	}
+	_ = m.client.RemoveObject(ctx, m.bucketName, stagingPath, minio.RemoveObjectOptions{})
 
 	m.log.Infow("File uploaded successfully",
 		"file_path", filePath,
 		"original_name", fileName,
 		"size", fileSize,
+		"checksum", checksum,
 	)
 
-	return filePath, nil
+	return filePath, checksum, nil
+}
+
+// PutAt writes a file directly to filePath, with no content-addressing or
+// dedup, for callers that need a deterministic, caller-chosen key.
+func (m *MinioStorage) PutAt(ctx context.Context, filePath string, reader io.Reader, contentType string) error {
+	_, err := m.client.PutObject(ctx, m.bucketName, filePath, reader, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		m.log.Errorw("Failed to put object to MinIO", "file_path", filePath, "error", err)
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	return nil
+}
+
+// Checksum re-hashes an object's current contents, for VerifyIntegrity to
+// compare against the checksum recorded at upload time.
+func (m *MinioStorage) Checksum(ctx context.Context, filePath string) (string, error) {
+	return checksumViaDownload(ctx, m, filePath)
 }
 
 // Download retrieves a file from MinIO storage
@@ -144,3 +221,144 @@ func (m *MinioStorage) GetURL(filePath string) string {
 	}
 	return fmt.Sprintf("%s/%s/%s", m.fileURL, m.bucketName, filePath)
 }
+
+// PresignUpload returns a new object path and a presigned PUT URL that lets a
+// client upload directly to MinIO, bypassing the gRPC server entirely.
+func (m *MinioStorage) PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (string, string, error) {
+	filePath := newObjectPath(fileName)
+
+	presignedURL, err := m.client.PresignedPutObject(ctx, m.bucketName, filePath, expiry)
+	if err != nil {
+		m.log.Errorw("Failed to presign upload URL",
+			"file_path", filePath,
+			"error", err,
+		)
+		return "", "", fmt.Errorf("failed to presign upload url: %w", err)
+	}
+
+	m.log.Infow("Presigned upload URL generated", "file_path", filePath, "expiry", expiry)
+	return filePath, presignedURL.String(), nil
+}
+
+// PresignDownload returns a presigned GET URL for an existing object,
+// optionally overriding the Content-Disposition/Content-Type the backend
+// responds with so browsers save the file under its original name.
+func (m *MinioStorage) PresignDownload(ctx context.Context, filePath string, expiry time.Duration, headers *ResponseHeaderOverrides) (string, error) {
+	reqParams := make(url.Values)
+	if headers != nil {
+		if headers.ContentDisposition != "" {
+			reqParams.Set("response-content-disposition", headers.ContentDisposition)
+		}
+		if headers.ContentType != "" {
+			reqParams.Set("response-content-type", headers.ContentType)
+		}
+	}
+
+	presignedURL, err := m.client.PresignedGetObject(ctx, m.bucketName, filePath, expiry, reqParams)
+	if err != nil {
+		m.log.Errorw("Failed to presign download URL",
+			"file_path", filePath,
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to presign download url: %w", err)
+	}
+
+	m.log.Infow("Presigned download URL generated", "file_path", filePath, "expiry", expiry)
+	return presignedURL.String(), nil
+}
+
+// InitMultipartUpload starts a resumable multipart upload.
+func (m *MinioStorage) InitMultipartUpload(ctx context.Context, fileName, contentType string) (string, string, error) {
+	filePath := newObjectPath(fileName)
+
+	uploadID, err := m.core.NewMultipartUpload(ctx, m.bucketName, filePath, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		m.log.Errorw("Failed to init multipart upload", "file_path", filePath, "error", err)
+		return "", "", fmt.Errorf("failed to init multipart upload: %w", err)
+	}
+
+	m.log.Infow("Multipart upload initiated", "file_path", filePath, "upload_id", uploadID)
+	return filePath, uploadID, nil
+}
+
+// UploadPart uploads a single part of a multipart upload.
+func (m *MinioStorage) UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, reader io.Reader, partSize int64) (string, error) {
+	part, err := m.core.PutObjectPart(ctx, m.bucketName, filePath, uploadID, partNumber, reader, partSize, minio.PutObjectPartOptions{})
+	if err != nil {
+		m.log.Errorw("Failed to upload part",
+			"file_path", filePath,
+			"upload_id", uploadID,
+			"part_number", partNumber,
+			"error", err,
+		)
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	return part.ETag, nil
+}
+
+// ListParts returns the parts already received for an in-progress multipart
+// upload, so a client can resume after an interruption without re-sending
+// parts it already uploaded.
+func (m *MinioStorage) ListParts(ctx context.Context, filePath, uploadID string) ([]MultipartPart, error) {
+	var parts []MultipartPart
+	partNumberMarker := 0
+
+	for {
+		result, err := m.core.ListObjectParts(ctx, m.bucketName, filePath, uploadID, partNumberMarker, 1000)
+		if err != nil {
+			m.log.Errorw("Failed to list parts", "file_path", filePath, "upload_id", uploadID, "error", err)
+			return nil, fmt.Errorf("failed to list parts: %w", err)
+		}
+
+		for _, p := range result.ObjectParts {
+			parts = append(parts, MultipartPart{
+				PartNumber: p.PartNumber,
+				ETag:       p.ETag,
+				Size:       p.Size,
+			})
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		partNumberMarker = result.NextPartNumberMarker
+	}
+
+	return parts, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final
+// object.
+func (m *MinioStorage) CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []MultipartPart) error {
+	completeParts := make([]minio.CompletePart, len(parts))
+	for i, p := range parts {
+		completeParts[i] = minio.CompletePart{
+			PartNumber: p.PartNumber,
+			ETag:       p.ETag,
+		}
+	}
+
+	_, err := m.core.CompleteMultipartUpload(ctx, m.bucketName, filePath, uploadID, completeParts, minio.PutObjectOptions{})
+	if err != nil {
+		m.log.Errorw("Failed to complete multipart upload", "file_path", filePath, "upload_id", uploadID, "error", err)
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	m.log.Infow("Multipart upload completed", "file_path", filePath, "upload_id", uploadID, "parts", len(parts))
+	return nil
+}
+
+// AbortMultipartUpload cancels an in-progress multipart upload and releases
+// any parts already uploaded.
+func (m *MinioStorage) AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error {
+	if err := m.core.AbortMultipartUpload(ctx, m.bucketName, filePath, uploadID); err != nil {
+		m.log.Errorw("Failed to abort multipart upload", "file_path", filePath, "upload_id", uploadID, "error", err)
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+
+	m.log.Infow("Multipart upload aborted", "file_path", filePath, "upload_id", uploadID)
+	return nil
+}
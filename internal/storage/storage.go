@@ -2,13 +2,37 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"time"
 )
 
+// MultipartPart describes one uploaded part of a multipart upload.
+type MultipartPart struct {
+	PartNumber int
+	ETag       string
+	Size       int64
+}
+
+// ResponseHeaderOverrides asks a presigned download to set Content-Disposition/Content-Type on response.
+type ResponseHeaderOverrides struct {
+	ContentDisposition string
+	ContentType        string
+}
+
 // Storage defines the interface for file storage operations
 type Storage interface {
-	// Upload uploads a file to storage and returns the file path/key
-	Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (string, error)
+	// Upload uploads a file to storage and returns the file path/key along
+	// with the SHA-256 digest computed while streaming it, so callers don't
+	// have to re-download the object just to learn its checksum.
+	Upload(ctx context.Context, fileName string, fileSize int64, reader io.Reader, contentType string) (filePath string, checksum string, err error)
+
+	// PutAt writes a file at the exact filePath given, bypassing content
+	// addressing. It's for callers (like derivative caching) that need a
+	// deterministic, caller-chosen key rather than one derived from the
+	// content's checksum.
+	PutAt(ctx context.Context, filePath string, reader io.Reader, contentType string) error
 
 	// Download retrieves a file from storage
 	Download(ctx context.Context, filePath string) (io.ReadCloser, error)
@@ -18,4 +42,57 @@ type Storage interface {
 
 	// GetURL returns the public URL for accessing a file
 	GetURL(filePath string) string
+
+	// PresignUpload returns a file path/key the caller should upload to and a
+	// presigned URL that allows a client to PUT the object directly to
+	// storage, without the bytes passing through this service.
+	PresignUpload(ctx context.Context, fileName, contentType string, expiry time.Duration) (filePath string, url string, err error)
+
+	// PresignDownload returns a presigned URL that allows a client to GET an
+	// existing object directly from storage. headers, when non-nil,
+	// overrides the Content-Disposition/Content-Type the backend responds
+	// with so browsers save the file under its original name.
+	PresignDownload(ctx context.Context, filePath string, expiry time.Duration, headers *ResponseHeaderOverrides) (url string, err error)
+
+	// InitMultipartUpload starts a resumable multipart upload and returns the
+	// object path the parts will be assembled into and an upload ID that
+	// identifies the session for subsequent UploadPart/ListParts/Complete/Abort
+	// calls.
+	InitMultipartUpload(ctx context.Context, fileName, contentType string) (filePath string, uploadID string, err error)
+
+	// UploadPart uploads a single part of a multipart upload and returns its
+	// ETag, which must be supplied to CompleteMultipartUpload.
+	UploadPart(ctx context.Context, filePath, uploadID string, partNumber int, reader io.Reader, partSize int64) (etag string, err error)
+
+	// ListParts returns the parts already received for an in-progress
+	// multipart upload, letting a client resume after an interruption.
+	ListParts(ctx context.Context, filePath, uploadID string) ([]MultipartPart, error)
+
+	// CompleteMultipartUpload assembles the uploaded parts into the final
+	// object.
+	CompleteMultipartUpload(ctx context.Context, filePath, uploadID string, parts []MultipartPart) error
+
+	// AbortMultipartUpload cancels an in-progress multipart upload and
+	// releases any parts already uploaded.
+	AbortMultipartUpload(ctx context.Context, filePath, uploadID string) error
+
+	// Checksum re-hashes an object's current contents and returns its
+	// SHA-256 digest, for comparing against the checksum recorded at
+	// upload time to detect corruption or tampering.
+	Checksum(ctx context.Context, filePath string) (string, error)
+}
+
+// checksumViaDownload computes an object's SHA-256 digest by downloading and hashing it.
+func checksumViaDownload(ctx context.Context, s Storage, filePath string) (string, error) {
+	reader, err := s.Download(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
@@ -0,0 +1,51 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Encoder turns a decoded image into bytes of the requested format, paired
+// with the content type clients should be served it under. Swapping this
+// interface for a libvips- or ffmpeg-backed implementation (see the
+// "vips" build tag) lets heavier codecs (WebP, AVIF, video thumbnailing)
+// be plugged in without touching Service.
+type Encoder interface {
+	Encode(img image.Image, format string, quality int) (data []byte, contentType string, err error)
+}
+
+// stdEncoder encodes using only the standard library, covering JPEG and
+// PNG. It's the default so this service has no cgo dependency out of the
+// box; WebP/AVIF require the "vips" build tag.
+type stdEncoder struct{}
+
+// NewStdEncoder creates the default, pure-Go Encoder.
+func NewStdEncoder() Encoder {
+	return stdEncoder{}
+}
+
+// Encode implements Encoder.
+func (stdEncoder) Encode(img image.Image, format string, quality int) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "", "jpeg", "jpg":
+		if quality <= 0 {
+			quality = 85
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", fmt.Errorf("failed to encode png: %w", err)
+		}
+		return buf.Bytes(), "image/png", nil
+	default:
+		return nil, "", fmt.Errorf("format %q requires the vips-backed encoder (build with -tags vips)", format)
+	}
+}
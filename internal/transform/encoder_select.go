@@ -0,0 +1,11 @@
+//go:build !vips
+
+package transform
+
+// newDefaultEncoder returns the Encoder New wires into a Service. This
+// build has no libvips dependency, so it's just the standard-library
+// encoder; the vips-tagged build in encoder_vips.go overrides it to add
+// WebP/AVIF support.
+func newDefaultEncoder() Encoder {
+	return NewStdEncoder()
+}
@@ -0,0 +1,75 @@
+//go:build vips
+
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+// encodePNGForVips bridges a decoded Go image.Image into libvips, which
+// loads from encoded bytes rather than accepting image.Image directly.
+func encodePNGForVips(img image.Image) io.Reader {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return &buf
+}
+
+func init() {
+	vips.Startup(nil)
+}
+
+// vipsEncoder adds WebP and AVIF support on top of stdEncoder by calling
+// libvips through its govips bindings. Only built with `-tags vips`, since libvips is a cgo
+// dependency this service doesn't otherwise require.
+type vipsEncoder struct {
+	fallback Encoder
+}
+
+// NewVipsEncoder creates an Encoder that handles WebP/AVIF via libvips and
+// defers everything else to the standard-library encoder.
+func NewVipsEncoder() Encoder {
+	return vipsEncoder{fallback: NewStdEncoder()}
+}
+
+// newDefaultEncoder returns the Encoder New wires into a Service. Building
+// with -tags vips swaps in WebP/AVIF support via this override of the
+// default in encoder_select.go.
+func newDefaultEncoder() Encoder {
+	return NewVipsEncoder()
+}
+
+// Encode implements Encoder.
+func (e vipsEncoder) Encode(img image.Image, format string, quality int) ([]byte, string, error) {
+	switch format {
+	case "webp", "avif":
+		imgRef, err := vips.NewImageFromReader(encodePNGForVips(img))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load image into vips: %w", err)
+		}
+		defer imgRef.Close()
+
+		if quality <= 0 {
+			quality = 80
+		}
+
+		var data []byte
+		switch format {
+		case "webp":
+			data, _, err = imgRef.ExportWebp(&vips.WebpExportParams{Quality: quality})
+		case "avif":
+			data, _, err = imgRef.ExportAvif(&vips.AvifExportParams{Quality: quality})
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to export %s: %w", format, err)
+		}
+		return data, "image/" + format, nil
+	default:
+		return e.fallback.Encode(img, format, quality)
+	}
+}
@@ -0,0 +1,187 @@
+// Package transform generates and caches derivative renditions of uploaded images.
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+
+	"github.com/khdiyz/common/logger"
+	"github.com/khdiyz/media-service/internal/storage"
+	"golang.org/x/image/draw"
+)
+
+// Preset is a named derivative generated automatically for every uploaded
+// image, stored under the same key as the equivalent Options rendition,
+// "<path>/derivatives/<Options.key()>.<ext>" (Name is not part of the key).
+type Preset struct {
+	Name    string
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+}
+
+// DefaultPresets are generated for every image uploaded through
+// MediaService.UploadFile when a transform.Service is configured. Images
+// uploaded through UploadStream or a multipart upload get no eager
+// derivatives; GetDerivative renders them on first request.
+var DefaultPresets = []Preset{
+	{Name: "thumbnail", Width: 200, Height: 200, Format: "jpeg", Quality: 80},
+	{Name: "preview", Width: 1024, Height: 1024, Format: "jpeg", Quality: 85},
+}
+
+// Options describes an ad-hoc rendition requested via Transform.
+type Options struct {
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+}
+
+// key returns the deterministic cache key an Options value maps to,
+// distinct from a named Preset's key.
+func (o Options) key() string {
+	return fmt.Sprintf("w%d_h%d_%s_q%d", o.Width, o.Height, o.Format, o.Quality)
+}
+
+// Service generates and caches image derivatives.
+type Service struct {
+	storage storage.Storage
+	encoder Encoder
+	presets []Preset
+	log     *logger.Logger
+}
+
+// New creates a transform Service backed by storage for both reading
+// originals and caching derivatives.
+func New(backingStorage storage.Storage, log *logger.Logger) *Service {
+	return &Service{
+		storage: backingStorage,
+		encoder: newDefaultEncoder(),
+		presets: DefaultPresets,
+		log:     log,
+	}
+}
+
+// IsImage reports whether contentType is one this service knows how to
+// transform.
+func IsImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+func derivativeKey(filePath, name, format string) string {
+	return fmt.Sprintf("%s/derivatives/%s.%s", filePath, name, extFor(format))
+}
+
+func extFor(format string) string {
+	switch format {
+	case "", "jpeg":
+		return "jpg"
+	default:
+		return format
+	}
+}
+
+// GenerateDerivatives decodes content and produces every DefaultPresets
+// rendition, uploading each under its deterministic derivative key.
+func (s *Service) GenerateDerivatives(ctx context.Context, filePath string, content []byte) error {
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	for _, preset := range s.presets {
+		opts := Options{Width: preset.Width, Height: preset.Height, Format: preset.Format, Quality: preset.Quality}
+
+		data, contentType, err := s.render(img, opts.Width, opts.Height, opts.Format, opts.Quality)
+		if err != nil {
+			return fmt.Errorf("failed to render preset %q: %w", preset.Name, err)
+		}
+
+		// Keyed the same way Transform caches ad-hoc renditions, so
+		// GetDerivative's first request hits this eagerly-generated copy
+		// instead of re-rendering and uploading a duplicate.
+		key := derivativeKey(filePath, opts.key(), opts.Format)
+		if err := s.storage.PutAt(ctx, key, bytes.NewReader(data), contentType); err != nil {
+			return fmt.Errorf("failed to upload derivative %q: %w", preset.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetDerivative returns a named preset rendition of filePath, generating it
+// on first request if it isn't already cached in storage.
+func (s *Service) GetDerivative(ctx context.Context, filePath, presetName string) (io.ReadCloser, string, error) {
+	var preset *Preset
+	for i := range s.presets {
+		if s.presets[i].Name == presetName {
+			preset = &s.presets[i]
+			break
+		}
+	}
+	if preset == nil {
+		return nil, "", fmt.Errorf("unknown preset: %q", presetName)
+	}
+
+	return s.Transform(ctx, filePath, Options{Width: preset.Width, Height: preset.Height, Format: preset.Format, Quality: preset.Quality})
+}
+
+// Transform returns the rendition of filePath described by opts, generating
+// and caching it under a deterministic key on first request.
+func (s *Service) Transform(ctx context.Context, filePath string, opts Options) (io.ReadCloser, string, error) {
+	key := derivativeKey(filePath, opts.key(), opts.Format)
+	contentType := "image/" + extFor(opts.Format)
+	if opts.Format == "jpeg" || opts.Format == "" {
+		contentType = "image/jpeg"
+	}
+
+	if cached, err := s.storage.Download(ctx, key); err == nil {
+		return cached, contentType, nil
+	}
+
+	original, err := s.storage.Download(ctx, filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download original: %w", err)
+	}
+	defer original.Close()
+
+	content, err := io.ReadAll(original)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read original: %w", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	data, contentType, err := s.render(img, opts.Width, opts.Height, opts.Format, opts.Quality)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.storage.PutAt(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		s.log.Errorw("Failed to cache derivative", "file_path", filePath, "key", key, "error", err)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), contentType, nil
+}
+
+func (s *Service) render(img image.Image, width, height int, format string, quality int) ([]byte, string, error) {
+	resized := img
+	if width > 0 && height > 0 {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+		resized = dst
+	}
+
+	return s.encoder.Encode(resized, format, quality)
+}